@@ -0,0 +1,176 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"math"
+)
+
+// Orientation is winding direction of closed subpath,
+// in SVG user space where y axis goes down
+type Orientation uint8
+
+const (
+	Degenerate Orientation = iota
+	Clockwise
+	CounterClockwise
+)
+
+// 5 point Gauss-Legendre on [0, 1], exact for polynomial up to degree 9
+var (
+	gaussNodes   = [...]float64{0.046910077030668, 0.230765344947158, 0.5, 0.769234655052842, 0.953089922969332}
+	gaussWeights = [...]float64{0.118463442528095, 0.239314335249683, 0.284444444444444, 0.239314335249683, 0.118463442528095}
+)
+
+func (s Orientation) String() string {
+	switch s {
+	case Clockwise:
+		return "Clockwise"
+	case CounterClockwise:
+		return "CounterClockwise"
+	}
+	return "Degenerate"
+}
+
+// SignedAreas return exact area of each subpath, open subpath is closed implicitly as filling does.
+// Area is positive when subpath is clockwise on screen.
+func (s *Renderer) SignedAreas() []float32 {
+	sps := s.subpaths()
+	res := make([]float32, len(sps))
+	for i, sp := range sps {
+		a, _, _ := sp.moments(sp.start)
+		res[i] = float32(a)
+	}
+	return res
+}
+
+// Orientations return winding direction of each subpath
+func (s *Renderer) Orientations() []Orientation {
+	areas := s.SignedAreas()
+	res := make([]Orientation, len(areas))
+	for i, a := range areas {
+		res[i] = orientationOf(a)
+	}
+	return res
+}
+
+// Area is sum of SignedAreas,
+// holes wound in opposite direction are subtracted
+func (s *Renderer) Area() (res float32) {
+	for _, a := range s.SignedAreas() {
+		res += a
+	}
+	return res
+}
+
+// Centroid return area weighted center of whole path.
+// Subpaths are weighted with their signed area, so holes must wind in opposite direction of outer.
+// If total area is zero, it return false
+func (s *Renderer) Centroid() (mgl32.Vec2, bool) {
+	sps := s.subpaths()
+	if len(sps) == 0 {
+		return mgl32.Vec2{}, false
+	}
+	ref := sps[0].start
+	var area, mx, my float64
+	for _, sp := range sps {
+		a, x, y := sp.moments(ref)
+		area, mx, my = area+a, mx+x, my+y
+	}
+	if area == 0 || math.IsNaN(area) {
+		return mgl32.Vec2{}, false
+	}
+	return mgl32.Vec2{float32(mx/area) + ref[0], float32(my/area) + ref[1]}, true
+}
+
+func orientationOf(area float32) Orientation {
+	switch {
+	case area > 0:
+		return Clockwise
+	case area < 0:
+		return CounterClockwise
+	}
+	return Degenerate
+}
+
+// moments compute area and first moments of closed subpath by Green's theorem,
+// coordinates are relative to 'ref' for precision.
+//
+// A = 1/2 ∮ x dy - y dx
+// Mx = 1/3 ∮ x (x dy - y dx)
+// My = 1/3 ∮ y (x dy - y dx)
+func (s subpath) moments(ref mgl32.Vec2) (area, mx, my float64) {
+	for _, g := range s.ring() {
+		a, x, y := g.moments(ref)
+		area, mx, my = area+a, mx+x, my+y
+	}
+	return area, mx, my
+}
+
+func (g segment) moments(ref mgl32.Vec2) (area, mx, my float64) {
+	switch g.kind {
+	case seg.LINETO_ABS:
+		a, b := toPoint(g.from.Sub(ref)), toPoint(g.to.Sub(ref))
+		c := a.x*b.y - a.y*b.x
+		return c / 2, c * (a.x + b.x) / 6, c * (a.y + b.y) / 6
+	case seg.ARC_ABS:
+		p := g.arcParam()
+		p.cx -= float64(ref[0])
+		p.cy -= float64(ref[1])
+		area = p.area()
+		// each piece is less than 45 degree, quadrature error is far below float32
+		n := int(math.Ceil(math.Abs(p.delta) / (math.Pi / 4)))
+		for i := 0; i < n; i++ {
+			_, x, y := integrate(func(t float64) (point, point) {
+				theta := p.theta + p.delta*(float64(i)+t)/float64(n)
+				return p.point64(theta), p.derivative64(theta, p.delta/float64(n))
+			})
+			mx, my = mx+x, my+y
+		}
+		return area, mx, my
+	}
+	// polynomial curve, quadrature is exact
+	return integrate(func(t float64) (point, point) {
+		return toPoint(g.at(float32(t)).Sub(ref)), toPoint(g.derivative(float32(t)))
+	})
+}
+
+// integrate area and moments along curve 'fn', which return point and derivative on t
+func integrate(fn func(t float64) (p, d point)) (area, mx, my float64) {
+	for i, t := range gaussNodes {
+		p, d := fn(t)
+		c := (p.x*d.y - p.y*d.x) * gaussWeights[i]
+		area += c / 2
+		mx += p.x * c / 3
+		my += p.y * c / 3
+	}
+	return area, mx, my
+}
+
+// area is exact value of 1/2 ∫ x dy - y dx along arc
+func (a arcParam) area() float64 {
+	t0, t1 := a.theta, a.theta+a.delta
+	ux, uy := a.rx*a.cos, a.rx*a.sin
+	vx, vy := -a.ry*a.sin, a.ry*a.cos
+	cu := a.cx*uy - a.cy*ux
+	cv := a.cx*vy - a.cy*vx
+	uv := ux*vy - uy*vx
+	return (cu*(math.Cos(t1)-math.Cos(t0)) + cv*(math.Sin(t1)-math.Sin(t0)) + uv*a.delta) / 2
+}
+
+func (a arcParam) point64(theta float64) point {
+	s, c := math.Sincos(theta)
+	return point{
+		a.cx + a.rx*a.cos*c - a.ry*a.sin*s,
+		a.cy + a.rx*a.sin*c + a.ry*a.cos*s,
+	}
+}
+
+// derivative64 is derivative of point by theta, multiplied by 'scale'
+func (a arcParam) derivative64(theta, scale float64) point {
+	s, c := math.Sincos(theta)
+	return point{
+		(-a.rx*a.cos*s - a.ry*a.sin*c) * scale,
+		(-a.rx*a.sin*s + a.ry*a.cos*c) * scale,
+	}
+}
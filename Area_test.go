@@ -0,0 +1,40 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_SignedAreas(t *testing.T) {
+	for src, expect := range map[string]float32{
+		"M0,0 H10 V10 H0 Z":                            100,
+		"M0,0 V10 H10 V0 Z":                            -100,
+		"M10,0 A10,10 0 0 1 -10,0 A10,10 0 0 1 10,0 Z": math.Pi * 100,
+		"M0,0 Q5,10 10,0 Z":                            -100. / 3.,
+		"M0,0 C0,10 10,10 10,0 Z":                      -60,
+	} {
+		r, _ := NewRendererFromReader(strings.NewReader(src))
+		areas := r.SignedAreas()
+		if len(areas) != 1 || !mgl32.FloatEqualThreshold(areas[0], expect, 1e-3) {
+			t.Error(src, "must have area", expect, "but", areas)
+		}
+	}
+}
+
+func TestRenderer_Centroid(t *testing.T) {
+	// square with hole on right side
+	r, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z M5,0 V10 H10 V0 Z"))
+	c, ok := r.Centroid()
+	if !ok || !c.ApproxEqual(mgl32.Vec2{2.5, 5}) {
+		t.Error("centroid must be (2.5, 5), but", c)
+	}
+	if o := r.Orientations(); o[0] != Clockwise || o[1] != CounterClockwise {
+		t.Error("orientations are wrong", o)
+	}
+	circle, _ := NewRendererFromReader(strings.NewReader("M13,2 A10,10 0 0 1 -7,2 A10,10 0 0 1 13,2 Z"))
+	if c, ok := circle.Centroid(); !ok || !mgl32.FloatEqualThreshold(c[0], 3, 1e-4) || !mgl32.FloatEqualThreshold(c[1], 2, 1e-4) {
+		t.Error("circle centroid must be (3, 2), but", c)
+	}
+}
@@ -0,0 +1,134 @@
+package psvg
+
+import (
+	"math"
+	"sort"
+)
+
+// Contour is a subpath placed in containment tree.
+// Contours are expected not to cross each other, use ResolveSelfIntersections before if they do.
+type Contour struct {
+	// Index of subpath in Renderer
+	Index int
+	Path  *Renderer
+	// Winding number of area just inside of contour
+	Winding int
+	// Outer is true when outside is empty and inside is filled
+	Outer bool
+	// Hole is true when outside is filled and inside is empty
+	Hole     bool
+	Children []*Contour
+	// flattened subpath
+	ring []point
+	area float64
+}
+
+// Subpaths split path into each subpath, which has its own MoveToAbs
+func (s *Renderer) Subpaths() []*Renderer {
+	sps := s.subpaths()
+	res := make([]*Renderer, len(sps))
+	for i, sp := range sps {
+		res[i] = NewRenderer(sp.elems()...)
+	}
+	return res
+}
+
+// Contours build containment tree of subpaths and return top level contours.
+// Child is the contour directly inside of parent, Outer and Hole are decided by 'rule'.
+// Curves are flattened with 'tolerance' for containment test.
+func (s *Renderer) Contours(rule FillRule, tolerance float32) []*Contour {
+	return contours(s.subpaths(), rule, tolerance)
+}
+
+func contours(sps []subpath, rule FillRule, tolerance float32) []*Contour {
+	all := make([]*Contour, len(sps))
+	for i, sp := range sps {
+		ring := toPoints(sp.flatten(tolerance))
+		area, _, _ := sp.moments(sp.start)
+		all[i] = &Contour{
+			Index: i,
+			Path:  NewRenderer(sp.elems()...),
+			ring:  ring,
+			area:  area,
+		}
+	}
+	// bigger contour first, so parent is always decided before child
+	order := make([]*Contour, len(all))
+	copy(order, all)
+	sort.SliceStable(order, func(i, j int) bool {
+		return math.Abs(order[i].area) > math.Abs(order[j].area)
+	})
+	var roots []*Contour
+	for i, c := range order {
+		var parent *Contour
+		// smallest container is parent
+		for j := i - 1; j >= 0; j-- {
+			if order[j].contains(c) {
+				parent = order[j]
+				break
+			}
+		}
+		outside := 0
+		if parent != nil {
+			outside = parent.Winding
+			parent.Children = append(parent.Children, c)
+		} else {
+			roots = append(roots, c)
+		}
+		c.Winding = outside
+		switch {
+		case c.area > 0:
+			c.Winding++
+		case c.area < 0:
+			c.Winding--
+		}
+		c.Outer = !rule.filled(outside) && rule.filled(c.Winding)
+		c.Hole = rule.filled(outside) && !rule.filled(c.Winding)
+	}
+	return roots
+}
+
+// contains report most points of 'o' are inside of s
+func (s *Contour) contains(o *Contour) bool {
+	if math.Abs(s.area) <= math.Abs(o.area) || len(s.ring) < 3 {
+		return false
+	}
+	edges := ringToEdges(s.ring)
+	var in, out int
+	for _, p := range o.ring {
+		if winding(edges, p) != 0 {
+			in++
+		} else {
+			out++
+		}
+	}
+	return in > out
+}
+
+// Holes return children which are hole
+func (s *Contour) Holes() (res []*Contour) {
+	for _, c := range s.Children {
+		if c.Hole {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Walk visit contour and its descendants in depth first order
+func (s *Contour) Walk(fn func(c *Contour)) {
+	fn(s)
+	for _, c := range s.Children {
+		c.Walk(fn)
+	}
+}
+
+func ringToEdges(ring []point) []edge {
+	res := make([]edge, 0, len(ring))
+	for i := range ring {
+		if e := (edge{a: ring[i], b: ring[(i+1)%len(ring)]}); e.a != e.b {
+			res = append(res, e)
+		}
+	}
+	return res
+}
@@ -0,0 +1,27 @@
+package psvg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Contours(t *testing.T) {
+	// two squares in same direction, one more square inside of second one
+	r, _ := NewRendererFromReader(strings.NewReader("M0,0 H30 V30 H0 Z M10,10 H20 V20 H10 Z M12,12 H18 V18 H12 Z M40,0 H50 V10 H40 Z"))
+	if n := len(r.Subpaths()); n != 4 {
+		t.Fatal("path has 4 subpaths, but", n)
+	}
+	roots := r.Contours(EvenOdd, 0)
+	if len(roots) != 2 {
+		t.Fatal("there must be 2 top level contours, but", len(roots))
+	}
+	if !roots[0].Outer || len(roots[0].Holes()) != 1 {
+		t.Fatal("first contour must be outer with 1 hole")
+	}
+	if inner := roots[0].Holes()[0]; inner.Index != 1 || len(inner.Children) != 1 || !inner.Children[0].Outer {
+		t.Error("second contour must be hole and contain outer contour")
+	}
+	if roots := r.Contours(NonZero, 0); roots[0].Children[0].Hole {
+		t.Error("nonzero makes no hole when subpaths have same direction")
+	}
+}
@@ -0,0 +1,365 @@
+package psvg
+
+import (
+	"encoding/xml"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pkg/errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Document is SVG file, parsed to lightweight tree of elements
+type Document struct {
+	// Size of viewport of root <svg>, in pixels
+	Width, Height float32
+	// Transform maps user space of root <svg> to viewport, made from viewBox and preserveAspectRatio
+	Transform mgl32.Mat3
+	root      *node
+	ids       map[string]*node
+	// size of viewport in user space of root <svg>
+	inner mgl32.Vec2
+}
+
+// Shape is geometry of one element in Document
+type Shape struct {
+	// id attribute, may be empty
+	ID string
+	// Path in user space of the element
+	Path *Renderer
+	// Transform maps Path to viewport of Document, accumulated from all ancestors
+	Transform mgl32.Mat3
+	// Style inherited from ancestors
+	Style Style
+}
+
+// node is XML element
+type node struct {
+	name     string
+	attrs    map[string]string
+	children []*node
+	parent   *node
+}
+
+// Lengths of absolute units in pixels
+// https://www.w3.org/TR/css-values-3/#absolute-lengths
+var units = map[string]float32{
+	"px": 1,
+	"in": 96,
+	"cm": 96 / 2.54,
+	"mm": 96 / 25.4,
+	"pt": 4. / 3.,
+	"pc": 16,
+}
+
+// NewDocumentFromReader parse SVG file. Root element must be <svg>
+func NewDocumentFromReader(src io.Reader) (*Document, error) {
+	res := &Document{ids: make(map[string]*node)}
+	dec := xml.NewDecoder(src)
+	var cur *node
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "svg")
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name.Local, attrs: make(map[string]string), parent: cur}
+			for _, a := range t.Attr {
+				// plain href of SVG 2 is preferred to xlink:href
+				if _, ok := n.attrs[a.Name.Local]; ok && a.Name.Space != "" {
+					continue
+				}
+				n.attrs[a.Name.Local] = a.Value
+			}
+			if id, ok := n.attrs["id"]; ok {
+				if _, dup := res.ids[id]; !dup {
+					res.ids[id] = n
+				}
+			}
+			if cur == nil {
+				if res.root != nil {
+					return nil, errors.New("svg has several root elements")
+				}
+				res.root = n
+			} else {
+				cur.children = append(cur.children, n)
+			}
+			cur = n
+		case xml.EndElement:
+			if cur != nil {
+				cur = cur.parent
+			}
+		}
+	}
+	if res.root == nil || res.root.name != "svg" {
+		return nil, errors.New("root element of svg must be <svg>")
+	}
+	// without parent viewport, missing size is size of viewBox
+	var parent mgl32.Vec2
+	if vb, ok := res.root.viewBox(); ok {
+		parent = mgl32.Vec2{vb[2], vb[3]}
+	}
+	res.Width, res.Height = size(res.root, nil, parent)
+	res.Transform, res.inner = viewport(res.root, nil, parent)
+	// x and y of outermost <svg> has no effect
+	res.Transform = mgl32.Translate2D(-res.root.number("x", 0), -res.root.number("y", 0)).Mul3(res.Transform)
+	return res, nil
+}
+
+// Shapes return every rendered path and basic shape in document order,
+// including instances of <use>. Elements in error and circular references are skipped,
+// path data is used until its error as SVG does
+func (s *Document) Shapes() []*Shape {
+	return s.walk(s.Transform)
+}
+
+// FlattenSVG read SVG file and return path data of Document.Flatten
+func FlattenSVG(src io.Reader, union bool, tolerance float32) (string, error) {
+	doc, err := NewDocumentFromReader(src)
+	if err != nil {
+		return "", err
+	}
+	return PathData(doc.Flatten(union, tolerance)...), nil
+}
+
+// Flatten merge every visible shape to one path in user space of root <svg>.
+// When 'union' is true, areas filled by 'fill-rule' of each shape are merged to closed polygons without overlap,
+// shapes with fill="none" are skipped, curves are flattened with 'tolerance' and open lines vanish
+func (s *Document) Flatten(union bool, tolerance float32) (res []Elem) {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	var rings [][]point
+	for _, sh := range s.walk(mgl32.Ident3()) {
+		if !sh.Style.Visible || (union && sh.Style.Fill.None) {
+			continue
+		}
+		elems := sh.Path.Transform(sh.Transform)
+		if !union {
+			res = append(res, elems...)
+			continue
+		}
+		var shape [][]point
+		for _, sp := range NewRenderer(elems...).subpaths() {
+			shape = append(shape, toPoints(sp.flatten(tolerance)))
+		}
+		rings = append(rings, resolveRings(shape, sh.Style.FillRule.filled)...)
+	}
+	if !union {
+		return res
+	}
+	// every ring has filled area on its left
+	return ringsElems(resolveRings(rings, func(w int) bool { return w > 0 }))
+}
+
+// walk collect shapes, 'ctm' maps user space of root <svg> to result
+func (s *Document) walk(ctm mgl32.Mat3) (res []*Shape) {
+	tree := []*node{s.root}
+	for _, c := range s.root.children {
+		res = append(res, s.shapes(c, ctm, s.inner, tree)...)
+	}
+	return res
+}
+
+// shapes collect shapes in 'n', 'ctm' maps parent of 'n' to viewport.
+// 'vp' is size of nearest viewport in user space of parent of 'n'.
+// 'tree' is rendered ancestors of 'n', which has <use> in place of parent of referenced element
+func (s *Document) shapes(n *node, ctm mgl32.Mat3, vp mgl32.Vec2, tree []*node) (res []*Shape) {
+	for _, p := range tree {
+		if p == n {
+			// circular reference
+			return nil
+		}
+	}
+	if !n.displayed() {
+		return nil
+	}
+	tree = append(tree[:len(tree):len(tree)], n)
+	children := n.children
+	switch n.name {
+	case "svg":
+		// nested viewport
+		var m mgl32.Mat3
+		m, vp = viewport(n, nil, vp)
+		ctm = ctm.Mul3(n.transform()).Mul3(m)
+	case "g", "a", "switch":
+		ctm = ctm.Mul3(n.transform())
+	case "use":
+		ref := s.reference(n)
+		if ref == nil {
+			return nil
+		}
+		ctm = ctm.Mul3(n.transform()).Mul3(mgl32.Translate2D(n.number("x", 0), n.number("y", 0)))
+		if ref.name != "symbol" && ref.name != "svg" {
+			return s.shapes(ref, ctm, vp, tree)
+		}
+		// <symbol> is rendered as <svg>, sized by <use>
+		for _, p := range tree {
+			if p == ref {
+				return nil
+			}
+		}
+		tree = append(tree, ref)
+		var m mgl32.Mat3
+		m, vp = viewport(ref, n, vp)
+		ctm = ctm.Mul3(m)
+		children = ref.children
+	default:
+		if path := n.path(); path != nil {
+			return []*Shape{{ID: n.attrs["id"], Path: path, Transform: ctm.Mul3(n.transform()), Style: style(tree)}}
+		}
+		// not rendered directly, like <defs> and <symbol>
+		return nil
+	}
+	for _, c := range children {
+		res = append(res, s.shapes(c, ctm, vp, tree)...)
+	}
+	return res
+}
+
+// reference return element referenced by 'href' of 'n', nil for missing or external one
+func (s *Document) reference(n *node) *node {
+	href := strings.TrimSpace(n.attrs["href"])
+	if !strings.HasPrefix(href, "#") {
+		return nil
+	}
+	return s.ids[href[1:]]
+}
+
+// path of shape element, nil if it is not shape or it is in error
+func (s *node) path() *Renderer {
+	var elems []Elem
+	switch s.name {
+	case "path":
+		p := NewParser(strings.NewReader(s.attrs["d"]))
+		for e := p.Next(); e != nil; e = p.Next() {
+			if _, ok := e.(UnknownError); ok {
+				break
+			}
+			elems = append(elems, e)
+		}
+	case "rect":
+		elems = Rect(s.number("x", 0), s.number("y", 0), s.number("width", 0), s.number("height", 0), s.number("rx", -1), s.number("ry", -1))
+	case "circle":
+		elems = Circle(s.number("cx", 0), s.number("cy", 0), s.number("r", 0))
+	case "ellipse":
+		elems = Ellipse(s.number("cx", 0), s.number("cy", 0), s.number("rx", -1), s.number("ry", -1))
+	case "line":
+		elems = Line(s.number("x1", 0), s.number("y1", 0), s.number("x2", 0), s.number("y2", 0))
+	case "polyline":
+		elems, _ = ParsePolyline(s.attrs["points"])
+	case "polygon":
+		elems, _ = ParsePolygon(s.attrs["points"])
+	default:
+		return nil
+	}
+	if len(elems) == 0 {
+		return nil
+	}
+	return NewRenderer(elems...)
+}
+
+// transform attribute, invalid one is ignored
+func (s *node) transform() mgl32.Mat3 {
+	m, err := ParseTransform(s.attrs["transform"])
+	if err != nil {
+		return mgl32.Ident3()
+	}
+	return m
+}
+
+// number parse length attribute in user unit, 'auto', missing and invalid value are 'def'.
+// Percentage is not supported and treated as invalid
+func (s *node) number(name string, def float32) float32 {
+	v, ok := s.attrs[name]
+	if !ok {
+		return def
+	}
+	if f, ok := parseLength(v); ok {
+		return f
+	}
+	return def
+}
+
+func parseLength(v string) (float32, bool) {
+	v = strings.TrimSpace(v)
+	scale := float32(1)
+	for unit, px := range units {
+		if strings.HasSuffix(v, unit) {
+			v, scale = strings.TrimSuffix(v, unit), px
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 32)
+	if err != nil {
+		return 0, false
+	}
+	return float32(f) * scale, true
+}
+
+// size return size of viewport of <svg> or <symbol>, 'use' overrides it when it is not nil.
+// Missing size is 100%, which is size of 'parent' viewport
+func size(n, use *node, parent mgl32.Vec2) (width, height float32) {
+	width, height = n.number("width", parent[0]), n.number("height", parent[1])
+	if use != nil {
+		width, height = use.number("width", width), use.number("height", height)
+	}
+	return max32(width, 0), max32(height, 0)
+}
+
+// viewBox attribute, it is invalid without positive size
+func (s *node) viewBox() ([]float32, bool) {
+	vb, err := floats([]byte(s.attrs["viewBox"]))
+	return vb, err == nil && len(vb) == 4 && vb[2] > 0 && vb[3] > 0
+}
+
+// viewport return transform from viewBox of <svg> or <symbol> to coordinate of its parent,
+// and size of the viewport in its own user space.
+// It is sized by 'use' when it is not nil, and in 'parent' viewport.
+// https://www.w3.org/TR/SVG11/coords.html#PreserveAspectRatioAttribute
+func viewport(n, use *node, parent mgl32.Vec2) (mgl32.Mat3, mgl32.Vec2) {
+	width, height := size(n, use, parent)
+	vb, ok := n.viewBox()
+	m := mgl32.Translate2D(n.number("x", 0), n.number("y", 0))
+	if !ok {
+		return m, mgl32.Vec2{width, height}
+	}
+	sx, sy := width/vb[2], height/vb[3]
+	fields := strings.Fields(n.attrs["preserveAspectRatio"])
+	if len(fields) > 0 && fields[0] == "defer" {
+		fields = fields[1:]
+	}
+	align, slice := "xMidYMid", false
+	if len(fields) > 0 {
+		align = fields[0]
+	}
+	if len(fields) > 1 {
+		slice = fields[1] == "slice"
+	}
+	tx, ty := float32(0), float32(0)
+	if align != "none" {
+		if slice {
+			sx = max32(sx, sy)
+		} else {
+			sx = min32(sx, sy)
+		}
+		sy = sx
+		switch {
+		case strings.HasPrefix(align, "xMid"):
+			tx = (width - vb[2]*sx) / 2
+		case strings.HasPrefix(align, "xMax"):
+			tx = width - vb[2]*sx
+		}
+		switch {
+		case strings.HasSuffix(align, "YMid"):
+			ty = (height - vb[3]*sy) / 2
+		case strings.HasSuffix(align, "YMax"):
+			ty = height - vb[3]*sy
+		}
+	}
+	return m.Mul3(mgl32.Translate2D(tx-vb[0]*sx, ty-vb[1]*sy)).Mul3(mgl32.Scale2D(sx, sy)), mgl32.Vec2{vb[2], vb[3]}
+}
@@ -0,0 +1,209 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"strings"
+	"testing"
+)
+
+const documentTest = `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100" viewBox="0 0 100 100">
+	<title>test</title>
+	<defs><rect id="hidden" width="10" height="10"/></defs>
+	<g transform="translate(10 0)">
+		<rect id="box" x="0" y="0" width="10" height="20"/>
+		<g transform="scale(2)">
+			<circle id="dot" cx="5" cy="5" r="1" transform="translate(1,1)"/>
+		</g>
+	</g>
+	<path id="broken" d="M0 0 L10 0 L10 10 Z M 5 5 X"/>
+	<ellipse rx="0" ry="0"/>
+	<polygon points="0,0 4,0 4,3"/>
+</svg>`
+
+func TestDocument(t *testing.T) {
+	doc, err := NewDocumentFromReader(strings.NewReader(documentTest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Width != 200 || doc.Height != 100 {
+		t.Fatal("viewport must be 200x100, but", doc.Width, doc.Height)
+	}
+	shapes := doc.Shapes()
+	var ids []string
+	for _, s := range shapes {
+		ids = append(ids, s.ID)
+	}
+	if strings.Join(ids, ",") != "box,dot,broken," {
+		t.Fatal("unexpected shapes", ids)
+	}
+	// viewBox is centered with scale 1
+	if p := shapes[0].Transform.Mul3x1(mgl32.Vec3{0, 0, 1}); !p.Vec2().ApproxEqual(mgl32.Vec2{60, 0}) {
+		t.Error("box must start on (60, 0), but", p)
+	}
+	if p := shapes[1].Transform.Mul3x1(mgl32.Vec3{5, 5, 1}); !p.Vec2().ApproxEqual(mgl32.Vec2{72, 12}) {
+		t.Error("center of dot must be on (72, 12), but", p)
+	}
+	if a := shapes[2].Path.Area(); !mgl32.FloatEqual(a, 50) {
+		t.Error("broken path must be rendered until error, but area", a)
+	}
+}
+
+func TestViewport(t *testing.T) {
+	for _, c := range []struct {
+		attrs    map[string]string
+		from, to mgl32.Vec2
+	}{
+		{map[string]string{"width": "200", "height": "100", "viewBox": "0 0 100 100", "preserveAspectRatio": "xMinYMin"}, mgl32.Vec2{100, 100}, mgl32.Vec2{100, 100}},
+		{map[string]string{"width": "200", "height": "100", "viewBox": "0 0 100 100", "preserveAspectRatio": "xMaxYMax meet"}, mgl32.Vec2{100, 100}, mgl32.Vec2{200, 100}},
+		{map[string]string{"width": "200", "height": "100", "viewBox": "0 0 100 100", "preserveAspectRatio": "xMidYMid slice"}, mgl32.Vec2{100, 100}, mgl32.Vec2{200, 150}},
+		{map[string]string{"width": "200", "height": "100", "viewBox": "0 0 100 100", "preserveAspectRatio": "none"}, mgl32.Vec2{100, 100}, mgl32.Vec2{200, 100}},
+		{map[string]string{"width": "1in", "height": "1in", "viewBox": "10 10 1 1"}, mgl32.Vec2{11, 11}, mgl32.Vec2{96, 96}},
+		{map[string]string{"width": "50", "height": "50"}, mgl32.Vec2{100, 100}, mgl32.Vec2{100, 100}},
+	} {
+		m, _ := viewport(&node{name: "svg", attrs: c.attrs}, nil, mgl32.Vec2{100, 100})
+		if p := m.Mul3x1(c.from.Vec3(1)).Vec2(); !p.ApproxEqual(c.to) {
+			t.Error(c.attrs, "must map", c.from, "to", c.to, "but", p)
+		}
+	}
+}
+
+const useTest = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink">
+	<defs>
+		<symbol id="icon" viewBox="0 0 10 10" width="5" height="5">
+			<rect id="square" width="10" height="10"/>
+		</symbol>
+		<g id="pair" transform="translate(1 0)">
+			<circle id="left" r="1"/>
+			<use id="right" xlink:href="#left" x="3"/>
+		</g>
+		<g id="loop"><use href="#loop"/><use href="#back"/></g>
+		<use id="back" href="#loop"/>
+	</defs>
+	<use href="#icon" x="100"/>
+	<use href="#icon" width="20" height="20"/>
+	<use href="#pair" transform="scale(2)"/>
+	<use href="#loop"/>
+	<use href="#missing"/>
+	<use href="other.svg#icon"/>
+</svg>`
+
+func TestUse(t *testing.T) {
+	doc, err := NewDocumentFromReader(strings.NewReader(useTest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shapes := doc.Shapes()
+	var ids []string
+	for _, s := range shapes {
+		ids = append(ids, s.ID)
+	}
+	if strings.Join(ids, ",") != "square,square,left,left" {
+		t.Fatal("unexpected shapes", ids)
+	}
+	for i, c := range []struct {
+		from, to mgl32.Vec2
+	}{
+		{mgl32.Vec2{10, 10}, mgl32.Vec2{105, 5}},
+		{mgl32.Vec2{10, 10}, mgl32.Vec2{20, 20}},
+		{mgl32.Vec2{0, 0}, mgl32.Vec2{2, 0}},
+		{mgl32.Vec2{0, 0}, mgl32.Vec2{8, 0}},
+	} {
+		if p := shapes[i].Transform.Mul3x1(c.from.Vec3(1)).Vec2(); !p.ApproxEqual(c.to) {
+			t.Error(i, "must map", c.from, "to", c.to, "but", p)
+		}
+	}
+}
+
+func TestUseSymbolSize(t *testing.T) {
+	// symbol without size fills viewport of <svg>
+	doc, err := NewDocumentFromReader(strings.NewReader(`<svg width="24" height="24"><symbol id="i" viewBox="0 0 512 512"><rect width="512" height="512"/></symbol><use href="#i"/></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shapes := doc.Shapes()
+	if len(shapes) != 1 {
+		t.Fatal("must have 1 shape, but", len(shapes))
+	}
+	if p := shapes[0].Transform.Mul3x1(mgl32.Vec3{512, 512, 1}).Vec2(); !p.ApproxEqualThreshold(mgl32.Vec2{24, 24}, 1e-4) {
+		t.Error("corner of symbol must be on (24, 24), but", p)
+	}
+
+	// viewport of nested <svg> is in user space of root viewBox
+	doc, err = NewDocumentFromReader(strings.NewReader(`<svg width="100" height="100" viewBox="0 0 50 50">
+		<svg viewBox="0 0 10 10"><use href="#i"/></svg>
+		<symbol id="i" viewBox="0 0 1 1"><rect width="1" height="1"/></symbol>
+	</svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := doc.Shapes()[0].Transform.Mul3x1(mgl32.Vec3{1, 1, 1}).Vec2(); !p.ApproxEqualThreshold(mgl32.Vec2{100, 100}, 1e-4) {
+		t.Error("corner of nested symbol must be on (100, 100), but", p)
+	}
+}
+
+func TestDocumentError(t *testing.T) {
+	for _, src := range []string{
+		`<svg><g></svg>`,
+		`<html/>`,
+		``,
+	} {
+		if _, err := NewDocumentFromReader(strings.NewReader(src)); err == nil {
+			t.Error(src, "must be error")
+		}
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	doc, err := NewDocumentFromReader(strings.NewReader(`<svg width="20" height="20" viewBox="0 0 10 10">
+		<rect width="4" height="4"/>
+		<g transform="translate(2 2)"><rect width="4" height="4"/></g>
+		<path d="M 16 16 h 2 v 2 h -2 z" transform="scale(.5)"/>
+		<line x2="10" y2="10"/>
+	</svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := NewRenderer(doc.Flatten(false, 0)...)
+	if a := merged.Area(); !mgl32.FloatEqual(a, 33) {
+		t.Error("merged path must have area 33, but", a)
+	}
+	if len(merged.subpaths()) != 4 {
+		t.Error("merged path must have 4 subpaths, but", len(merged.subpaths()))
+	}
+	union := NewRenderer(doc.Flatten(true, 0)...)
+	if a := union.Area(); !mgl32.FloatEqualThreshold(a, 29, 1e-4) {
+		t.Error("union must have area 29, but", a)
+	}
+	if len(union.subpaths()) != 2 {
+		t.Error("union must have 2 subpaths, but", len(union.subpaths()))
+	}
+}
+
+func TestFlattenSVG(t *testing.T) {
+	src := `<svg viewBox="0 0 10 10">
+		<rect width="4" height="4"/>
+		<rect x="2" width="4" height="4" fill="none" stroke="black"/>
+	</svg>`
+	d, err := FlattenSVG(strings.NewReader(src), false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != "M0 0L4 0L4 4L0 4ZM2 0L6 0L6 4L2 4Z" {
+		t.Error("unexpected path data", d)
+	}
+	d, err = FlattenSVG(strings.NewReader(src), true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRendererFromReader(strings.NewReader(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := r.Area(); !mgl32.FloatEqualThreshold(a, 16, 1e-4) {
+		t.Error("union must skip unfilled rect, but area", a)
+	}
+	if _, err := FlattenSVG(strings.NewReader(`<html/>`), true, 0); err == nil {
+		t.Error("invalid document must be error")
+	}
+}
@@ -0,0 +1,112 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// Easing map input progress in [0, 1] to output progress,
+// like CSS timing function and SMIL keySplines
+type Easing interface {
+	Ease(x float32) float32
+}
+
+// EasingFunc is function used as Easing
+type EasingFunc func(x float32) float32
+
+// CubicBezier is CSS cubic-bezier(X1, Y1, X2, Y2), also used for SMIL keySplines.
+// Curve starts on (0, 0) and ends on (1, 1), X1 and X2 must be in [0, 1]
+type CubicBezier struct {
+	X1, Y1, X2, Y2 float32
+}
+
+// https://www.w3.org/TR/css-easing-1/#step-position
+type StepPosition uint8
+
+const (
+	JumpEnd StepPosition = iota
+	JumpStart
+	JumpNone
+	JumpBoth
+)
+
+// Steps is CSS steps(Count, Position)
+type Steps struct {
+	Count    int
+	Position StepPosition
+}
+
+// CSS named easings
+var (
+	Linear    Easing = EasingFunc(func(x float32) float32 { return x })
+	Ease      Easing = CubicBezier{.25, .1, .25, 1}
+	EaseIn    Easing = CubicBezier{.42, 0, 1, 1}
+	EaseOut   Easing = CubicBezier{0, 0, .58, 1}
+	EaseInOut Easing = CubicBezier{.42, 0, .58, 1}
+	StepStart Easing = Steps{1, JumpStart}
+	StepEnd   Easing = Steps{1, JumpEnd}
+)
+
+func (s EasingFunc) Ease(x float32) float32 {
+	return s(x)
+}
+
+// Ease solve x(t) = x, by newton method with fallback to bisection, and return y(t)
+func (s CubicBezier) Ease(x float32) float32 {
+	x = clamp01(x)
+	p0, p1, p2, p3 := mgl32.Vec2{}, mgl32.Vec2{clamp01(s.X1), s.Y1}, mgl32.Vec2{clamp01(s.X2), s.Y2}, mgl32.Vec2{1, 1}
+	t := x
+	solved := false
+	for i := 0; i < 8; i++ {
+		f := cubicAt(p0, p1, p2, p3, t)[0] - x
+		if abs32(f) < 1e-6 {
+			solved = true
+			break
+		}
+		d := cubicDerivative(p0, p1, p2, p3, t)[0]
+		if abs32(d) < 1e-6 {
+			break
+		}
+		t -= f / d
+		if t < 0 || t > 1 {
+			break
+		}
+	}
+	if !solved {
+		// x(t) is monotonic on [0, 1]
+		lo, hi := float32(0), float32(1)
+		t = x
+		for i := 0; i < 32 && hi-lo > 1e-7; i++ {
+			if cubicAt(p0, p1, p2, p3, t)[0] < x {
+				lo = t
+			} else {
+				hi = t
+			}
+			t = (lo + hi) / 2
+		}
+	}
+	return cubicAt(p0, p1, p2, p3, t)[1]
+}
+
+// https://www.w3.org/TR/css-easing-1/#step-easing-algo
+func (s Steps) Ease(x float32) float32 {
+	x = clamp01(x)
+	jumps := s.Count
+	switch s.Position {
+	case JumpNone:
+		jumps--
+	case JumpBoth:
+		jumps++
+	}
+	if s.Count < 1 || jumps < 1 {
+		return x
+	}
+	step := int(math.Floor(float64(x) * float64(s.Count)))
+	if s.Position == JumpStart || s.Position == JumpBoth {
+		step++
+	}
+	if step > jumps {
+		step = jumps
+	}
+	return float32(step) / float32(jumps)
+}
@@ -0,0 +1,45 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+func TestCubicBezier_Ease(t *testing.T) {
+	for x, expect := range map[float32]float32{0: 0, .25: .40851, .5: .80240, .75: .96046, 1: 1} {
+		if y := Ease.Ease(x); !mgl32.FloatEqualThreshold(y, expect, 1e-4) {
+			t.Error("ease on", x, "must be", expect, "but", y)
+		}
+	}
+	// linear curve
+	for x := float32(0); x <= 1; x += .125 {
+		if y := (CubicBezier{1. / 3., 1. / 3., 2. / 3., 2. / 3.}).Ease(x); !mgl32.FloatEqualThreshold(y, x, 1e-5) {
+			t.Error("linear bezier on", x, "must be", x, "but", y)
+		}
+	}
+	// x(t) = t^3, flat on start
+	if y := (CubicBezier{0, 1, 0, 1}).Ease(.001); !mgl32.FloatEqualThreshold(y, .271, 1e-4) {
+		t.Error("steep curve must be .271 on .001, but", y)
+	}
+}
+
+func TestSteps_Ease(t *testing.T) {
+	for _, c := range []struct {
+		steps  Steps
+		x, res float32
+	}{
+		{Steps{4, JumpEnd}, 0, 0},
+		{Steps{4, JumpEnd}, .3, .25},
+		{Steps{4, JumpEnd}, 1, 1},
+		{Steps{4, JumpStart}, 0, .25},
+		{Steps{4, JumpStart}, .3, .5},
+		{Steps{4, JumpNone}, .3, 1. / 3.},
+		{Steps{4, JumpNone}, .8, 1},
+		{Steps{4, JumpBoth}, 0, .2},
+		{Steps{4, JumpBoth}, 1, 1},
+	} {
+		if y := c.steps.Ease(c.x); !mgl32.FloatEqual(y, c.res) {
+			t.Error(c.steps, "on", c.x, "must be", c.res, "but", y)
+		}
+	}
+}
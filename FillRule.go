@@ -0,0 +1,26 @@
+package psvg
+
+// https://www.w3.org/TR/SVG/painting.html#FillRuleProperty
+type FillRule uint8
+
+const (
+	NonZero FillRule = iota
+	EvenOdd
+)
+
+func (s FillRule) String() string {
+	switch s {
+	case EvenOdd:
+		return "evenodd"
+	}
+	return "nonzero"
+}
+
+// filled report that point which has winding number 'w' is inside
+func (s FillRule) filled(w int) bool {
+	switch s {
+	case EvenOdd:
+		return w%2 != 0
+	}
+	return w != 0
+}
@@ -0,0 +1,172 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"math"
+)
+
+// Points turning more than this degree are corner by default
+const defaultFitCorner = 60
+
+// Reparameterize at most this times before split
+const fitIterations = 4
+
+// FitCurve fit sequence of points by cubic beziers, by the algorithm of Philip J. Schneider.
+// Result is MoveToAbs followed by CurveToCubicAbs, every point is within 'maxError' from curve.
+// Direction change bigger than 'corner' degree at a point makes sharp corner,
+// zero means default 60 degree.
+// Curves are smooth between corners
+func FitCurve(points []mgl32.Vec2, maxError, corner float32) []Elem {
+	var pts []mgl32.Vec2
+	for _, p := range points {
+		if len(pts) == 0 || pts[len(pts)-1] != p {
+			pts = append(pts, p)
+		}
+	}
+	if len(pts) == 0 {
+		return nil
+	}
+	if maxError <= 0 {
+		maxError = defaultTolerance
+	}
+	if corner <= 0 {
+		corner = defaultFitCorner
+	}
+	res := []Elem{MoveToAbs{To: pts[0]}}
+	threshold := float32(math.Cos(float64(mgl32.DegToRad(corner))))
+	start := 0
+	for i := 1; i < len(pts); i++ {
+		if i < len(pts)-1 {
+			a, b := pts[i].Sub(pts[i-1]).Normalize(), pts[i+1].Sub(pts[i]).Normalize()
+			if a.Dot(b) >= threshold {
+				continue
+			}
+		}
+		run := pts[start : i+1]
+		left := run[1].Sub(run[0]).Normalize()
+		right := run[len(run)-2].Sub(run[len(run)-1]).Normalize()
+		for _, g := range fitCubic(run, left, right, maxError*maxError) {
+			res = append(res, g.elem())
+		}
+		start = i
+	}
+	return res
+}
+
+// fitCubic fit points with end tangents, split recursively when error is too big.
+// 'tolerance' is square of max error
+func fitCubic(pts []mgl32.Vec2, left, right mgl32.Vec2, tolerance float32) []segment {
+	g, split, ok := fitSingle(pts, left, right, tolerance)
+	if ok {
+		return []segment{g}
+	}
+	center := pts[split-1].Sub(pts[split+1])
+	if center.Len() == 0 {
+		center = pts[split-1].Sub(pts[split])
+	}
+	center = center.Normalize()
+	return append(fitCubic(pts[:split+1], left, center, tolerance), fitCubic(pts[split:], center.Mul(-1), right, tolerance)...)
+}
+
+// fitSingle fit points by one cubic, return index of farthest point when it fails
+func fitSingle(pts []mgl32.Vec2, left, right mgl32.Vec2, tolerance float32) (g segment, split int, ok bool) {
+	if len(pts) == 2 {
+		d := pts[1].Sub(pts[0]).Len() / 3
+		return segment{
+			kind: seg.CURVETO_CUBIC_ABS,
+			from: pts[0],
+			p0:   pts[0].Add(left.Mul(d)),
+			p1:   pts[1].Add(right.Mul(d)),
+			to:   pts[1],
+		}, 0, true
+	}
+	u := chordLength(pts)
+	g = generateBezier(pts, u, left, right)
+	err, split := fitError(pts, g, u)
+	if err < tolerance {
+		return g, split, true
+	}
+	// close enough, try better parameters before split
+	if err < tolerance*4 {
+		for i := 0; i < fitIterations; i++ {
+			u = reparameterize(pts, g, u)
+			g = generateBezier(pts, u, left, right)
+			if err, split = fitError(pts, g, u); err < tolerance {
+				return g, split, true
+			}
+		}
+	}
+	return g, split, false
+}
+
+// chordLength parameterize points by accumulated distance
+func chordLength(pts []mgl32.Vec2) []float32 {
+	u := make([]float32, len(pts))
+	for i := 1; i < len(pts); i++ {
+		u[i] = u[i-1] + pts[i].Sub(pts[i-1]).Len()
+	}
+	for i := range u {
+		u[i] /= u[len(u)-1]
+	}
+	return u
+}
+
+// generateBezier find lengths of tangents by least squares
+func generateBezier(pts []mgl32.Vec2, u []float32, left, right mgl32.Vec2) segment {
+	first, last := pts[0], pts[len(pts)-1]
+	var c00, c01, c11, x0, x1 float32
+	for i, p := range pts {
+		t := u[i]
+		b0, b1, b2, b3 := (1-t)*(1-t)*(1-t), 3*t*(1-t)*(1-t), 3*t*t*(1-t), t*t*t
+		a0, a1 := left.Mul(b1), right.Mul(b2)
+		c00 += a0.Dot(a0)
+		c01 += a0.Dot(a1)
+		c11 += a1.Dot(a1)
+		tmp := p.Sub(first.Mul(b0 + b1)).Sub(last.Mul(b2 + b3))
+		x0 += a0.Dot(tmp)
+		x1 += a1.Dot(tmp)
+	}
+	var alpha0, alpha1 float32
+	if det := c00*c11 - c01*c01; det != 0 {
+		alpha0 = (x0*c11 - x1*c01) / det
+		alpha1 = (c00*x1 - c01*x0) / det
+	}
+	// fall back to heuristic when solution is degenerate
+	chord := last.Sub(first).Len()
+	if eps := 1e-6 * chord; alpha0 < eps || alpha1 < eps {
+		alpha0, alpha1 = chord/3, chord/3
+	}
+	return segment{
+		kind: seg.CURVETO_CUBIC_ABS,
+		from: first,
+		p0:   first.Add(left.Mul(alpha0)),
+		p1:   last.Add(right.Mul(alpha1)),
+		to:   last,
+	}
+}
+
+// fitError return max squared distance and index of the point
+func fitError(pts []mgl32.Vec2, g segment, u []float32) (res float32, index int) {
+	index = len(pts) / 2
+	for i := 1; i < len(pts)-1; i++ {
+		d := g.at(u[i]).Sub(pts[i])
+		if l := d.Dot(d); l >= res {
+			res, index = l, i
+		}
+	}
+	return res, index
+}
+
+// reparameterize improve parameters by a step of newton method
+func reparameterize(pts []mgl32.Vec2, g segment, u []float32) []float32 {
+	res := make([]float32, len(u))
+	for i, t := range u {
+		q, d1, d2 := g.at(t).Sub(pts[i]), g.derivative(t), g.secondDerivative(t)
+		res[i] = t
+		if den := d1.Dot(d1) + q.Dot(d2); den != 0 {
+			res[i] = mgl32.Clamp(t-q.Dot(d1)/den, 0, 1)
+		}
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+func TestFitCurve(t *testing.T) {
+	// half circle, then straight line from sharp corner
+	var pts []mgl32.Vec2
+	for i := 0; i <= 50; i++ {
+		s, c := math.Sincos(math.Pi * float64(i) / 50)
+		pts = append(pts, mgl32.Vec2{float32(50 * c), float32(50 * s)})
+	}
+	for i := 1; i <= 10; i++ {
+		pts = append(pts, mgl32.Vec2{-50 - float32(i)*10, 0})
+	}
+	const maxError = .1
+	res := FitCurve(pts, maxError, 0)
+	if len(res) > 8 {
+		t.Error("too many curves", len(res)-1)
+	}
+	segs := NewRenderer(res...).subpaths()[0].segs
+	// corner is reproduced exactly, it is not (-50, 0) by rounding of sin(pi)
+	corner := false
+	for _, g := range segs {
+		if g.from == pts[50] {
+			corner = true
+		}
+	}
+	if !corner {
+		t.Error("corner must be end of curve")
+	}
+	for _, p := range pts {
+		d := float32(math.Inf(1))
+		for _, g := range segs {
+			gd, _ := g.nearest(p)
+			d = min32(d, gd)
+		}
+		if d > maxError*1.01 {
+			t.Error(p, "is far from curve", d)
+		}
+	}
+}
@@ -0,0 +1,537 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"sort"
+)
+
+type (
+	// Intersection is a point where subpath crosses or touches itself.
+	// Segments are indices of drawing commands in the subpath,
+	// implicit closing line has index after the last command.
+	// Both indices are same when a cubic bezier makes loop by itself.
+	Intersection struct {
+		Subpath  int
+		Segments [2]int
+		Point    mgl32.Vec2
+	}
+
+	// float64 point for robust planar computation
+	point struct {
+		x, y float64
+	}
+	edge struct {
+		a, b point
+		// index of segment in subpath
+		seg int
+	}
+	// link connects two vertices by index
+	link struct {
+		u, v int
+	}
+)
+
+// SelfIntersections find every point where a subpath crosses itself.
+// Subpaths are treated as closed, as filling does,
+// curves are flattened with 'tolerance'
+func (s *Renderer) SelfIntersections(tolerance float32) (res []Intersection) {
+	type hit struct {
+		Intersection
+		// flattened vertex the hit falls on
+		vertex point
+		shared bool
+	}
+	for i, sp := range s.subpaths() {
+		edges := ringEdges(sp, tolerance)
+		n := len(edges)
+		eps := extent(edges) * 1e-9
+		var hits []hit
+		eachOverlap(edges, func(a, b int) {
+			var common []point
+			if b == a+1 {
+				common = append(common, edges[a].b)
+			}
+			if a == 0 && b == n-1 {
+				common = append(common, edges[a].a)
+			}
+			p, ok := edgeIntersect(edges[a], edges[b])
+			if len(common) > 0 {
+				// adjacent edges always meet on common vertex
+				p, ok = backtrack(edges[a], edges[b], common, eps)
+			}
+			if ok {
+				v, shared := sharedVertex(edges[a], edges[b], p, eps)
+				if shared {
+					p = v
+				}
+				hits = append(hits, hit{
+					Intersection: Intersection{
+						Subpath:  i,
+						Segments: [2]int{edges[a].seg, edges[b].seg},
+						Point:    p.vec2(),
+					},
+					vertex: v,
+					shared: shared,
+				})
+			}
+		})
+		sort.SliceStable(hits, func(i, j int) bool {
+			if hits[i].Segments[0] != hits[j].Segments[0] {
+				return hits[i].Segments[0] < hits[j].Segments[0]
+			}
+			return hits[i].Segments[1] < hits[j].Segments[1]
+		})
+		// every pair of edges around one vertex meets on it, report it once
+		seen := make(map[point]bool)
+		for _, h := range hits {
+			if h.shared {
+				if seen[h.vertex] {
+					continue
+				}
+				seen[h.vertex] = true
+			}
+			res = append(res, h.Intersection)
+		}
+	}
+	return res
+}
+
+// backtrack return where adjacent edges e and f overlap on same line, except their 'common' vertices
+func backtrack(e, f edge, common []point, eps float64) (point, bool) {
+	te, tf := splitParams(e, f, eps)
+	var candidates []point
+	for _, t := range te {
+		candidates = append(candidates, e.at(t))
+	}
+	for _, t := range tf {
+		candidates = append(candidates, f.at(t))
+	}
+next:
+	for _, p := range candidates {
+		for _, c := range common {
+			if math.Hypot(p.x-c.x, p.y-c.y) <= eps {
+				continue next
+			}
+		}
+		return p, true
+	}
+	return point{}, false
+}
+
+// sharedVertex return end point of e or f, which p is on
+func sharedVertex(e, f edge, p point, eps float64) (point, bool) {
+	for _, v := range []point{e.a, e.b, f.a, f.b} {
+		if math.Hypot(p.x-v.x, p.y-v.y) <= eps {
+			return v, true
+		}
+	}
+	return point{}, false
+}
+
+// IsSelfIntersecting report any subpath crosses itself
+func (s *Renderer) IsSelfIntersecting(tolerance float32) bool {
+	return len(s.SelfIntersections(tolerance)) > 0
+}
+
+// ResolveSelfIntersections rebuild path as the outline of the area filled by 'rule'.
+// Every result contour is simple and never crosses another,
+// outer contours and holes wind in opposite direction.
+// Curves are flattened with 'tolerance', so result is made of LineToAbs only.
+func (s *Renderer) ResolveSelfIntersections(rule FillRule, tolerance float32) []Elem {
+	var rings [][]point
+	for _, sp := range s.subpaths() {
+		rings = append(rings, toPoints(sp.flatten(tolerance)))
+	}
+	return ringsElems(resolveRings(rings, rule.filled))
+}
+
+// resolveRings compute outline of area, which filled(winding number) is true.
+// Result rings are simple and have filled area on their left side.
+func resolveRings(rings [][]point, filled func(w int) bool) [][]point {
+	var edges []edge
+	for _, r := range rings {
+		edges = append(edges, ringToEdges(r)...)
+	}
+	if len(edges) == 0 {
+		return nil
+	}
+	scale := extent(edges)
+	// split every edge on every crossing
+	params := make([][]float64, len(edges))
+	for i := range params {
+		params[i] = []float64{0, 1}
+	}
+	eachOverlap(edges, func(a, b int) {
+		ta, tb := splitParams(edges[a], edges[b], scale*1e-9)
+		params[a] = append(params[a], ta...)
+		params[b] = append(params[b], tb...)
+	})
+	var (
+		vertices []point
+		index    = make(map[[2]int64]int)
+		quantum  = scale * 1e-9
+	)
+	vertex := func(p point) int {
+		key := [2]int64{int64(math.Round(p.x / quantum)), int64(math.Round(p.y / quantum))}
+		if i, ok := index[key]; ok {
+			return i
+		}
+		index[key] = len(vertices)
+		vertices = append(vertices, p)
+		return len(vertices) - 1
+	}
+	var (
+		links []link
+		// winding number on left side of each link minus right side
+		deltas []int
+		found  = make(map[link]int)
+	)
+	for i, e := range edges {
+		sort.Float64s(params[i])
+		prev := vertex(e.a)
+		for _, t := range params[i][1:] {
+			cur := vertex(e.at(t))
+			if cur == prev {
+				continue
+			}
+			key, d := link{prev, cur}, 1
+			if key.u > key.v {
+				key, d = link{cur, prev}, -1
+			}
+			j, ok := found[key]
+			if !ok {
+				j = len(links)
+				found[key] = j
+				links = append(links, key)
+				deltas = append(deltas, 0)
+			}
+			deltas[j] += d
+			prev = cur
+		}
+	}
+	// keep boundary links only, directed to have filled side on left
+	var boundary []link
+	left, right := linkWindings(vertices, links, deltas, edges, scale)
+	for i, l := range links {
+		switch a, b := filled(left[i]), filled(right[i]); {
+		case a && !b:
+			boundary = append(boundary, l)
+		case !a && b:
+			boundary = append(boundary, link{l.v, l.u})
+		}
+	}
+	// chain links to rings
+	outgoing := make(map[int][]int)
+	for i, l := range boundary {
+		outgoing[l.u] = append(outgoing[l.u], i)
+	}
+	used := make([]bool, len(boundary))
+	var res [][]point
+	for i := range boundary {
+		if used[i] {
+			continue
+		}
+		var (
+			ring  = []point{vertices[boundary[i].u]}
+			cur   = i
+			start = boundary[i].u
+		)
+		for {
+			used[cur] = true
+			v := boundary[cur].v
+			if v == start {
+				break
+			}
+			ring = append(ring, vertices[v])
+			back := vertices[boundary[cur].u]
+			next, best := -1, math.Inf(1)
+			for _, o := range outgoing[v] {
+				if used[o] {
+					continue
+				}
+				// clockwise angle from incoming edge reversed, to candidate
+				if a := clockwise(vertices[v], back, vertices[boundary[o].v]); a < best {
+					next, best = o, a
+				}
+			}
+			if next < 0 {
+				ring = nil
+				break
+			}
+			cur = next
+		}
+		if ring = simplifyRing(ring); len(ring) >= 3 {
+			res = append(res, ring)
+		}
+	}
+	return res
+}
+
+// linkWindings return winding numbers on left and right side of every link.
+// Links are traced to faces, winding number is counted once for each connected part
+// and stepped by 'deltas' to neighbor faces
+func linkWindings(vertices []point, links []link, deltas []int, edges []edge, scale float64) (left, right []int) {
+	// half link 2i goes along links[i], 2i+1 goes back
+	ends := func(h int) (int, int) {
+		if h%2 == 0 {
+			return links[h/2].u, links[h/2].v
+		}
+		return links[h/2].v, links[h/2].u
+	}
+	outgoing := make(map[int][]int)
+	for h := 0; h < 2*len(links); h++ {
+		u, _ := ends(h)
+		outgoing[u] = append(outgoing[u], h)
+	}
+	// face on left side of each half link, traced by turning left most
+	face := make([]int, 2*len(links))
+	for h := range face {
+		face[h] = -1
+	}
+	var around [][]int
+	for h := range face {
+		if face[h] >= 0 {
+			continue
+		}
+		f := len(around)
+		around = append(around, nil)
+		for cur := h; face[cur] < 0; {
+			face[cur] = f
+			around[f] = append(around[f], cur)
+			u, v := ends(cur)
+			next, best := -1, math.Inf(1)
+			for _, o := range outgoing[v] {
+				_, w := ends(o)
+				if a := clockwise(vertices[v], vertices[u], vertices[w]); a < best {
+					next, best = o, a
+				}
+			}
+			cur = next
+		}
+	}
+	wind := make([]int, len(around))
+	known := make([]bool, len(around))
+	for h := range face {
+		if known[face[h]] {
+			continue
+		}
+		// first face of connected part, count on a point next to h
+		u, v := ends(h)
+		a, b := vertices[u], vertices[v]
+		dx, dy := b.x-a.x, b.y-a.y
+		length := math.Hypot(dx, dy)
+		eps := math.Min(scale*1e-6, length/4)
+		wind[face[h]] = winding(edges, point{(a.x+b.x)/2 - dy/length*eps, (a.y+b.y)/2 + dx/length*eps})
+		known[face[h]] = true
+		for queue := []int{face[h]}; len(queue) > 0; queue = queue[1:] {
+			f := queue[0]
+			for _, g := range around[f] {
+				across := face[g^1]
+				if known[across] {
+					continue
+				}
+				d := deltas[g/2]
+				if g%2 == 1 {
+					d = -d
+				}
+				wind[across] = wind[f] - d
+				known[across] = true
+				queue = append(queue, across)
+			}
+		}
+	}
+	left, right = make([]int, len(links)), make([]int, len(links))
+	for i := range links {
+		left[i], right[i] = wind[face[2*i]], wind[face[2*i+1]]
+	}
+	return left, right
+}
+
+// winding number of p against edges, half open rule on y axis
+func winding(edges []edge, p point) (w int) {
+	for _, e := range edges {
+		if (e.a.y <= p.y) == (e.b.y <= p.y) {
+			continue
+		}
+		x := e.a.x + (p.y-e.a.y)/(e.b.y-e.a.y)*(e.b.x-e.a.x)
+		if x > p.x {
+			if e.b.y > e.a.y {
+				w++
+			} else {
+				w--
+			}
+		}
+	}
+	return w
+}
+
+// clockwise return angle in (0, 2pi] rotating from 'from' to 'to' around 'center'
+// in clockwise of y-up coordinate
+func clockwise(center, from, to point) float64 {
+	a := math.Atan2(from.y-center.y, from.x-center.x) - math.Atan2(to.y-center.y, to.x-center.x)
+	for a <= 0 {
+		a += 2 * math.Pi
+	}
+	for a > 2*math.Pi {
+		a -= 2 * math.Pi
+	}
+	return a
+}
+
+// simplifyRing remove collinear points
+func simplifyRing(ring []point) []point {
+	for changed := true; changed && len(ring) >= 3; {
+		changed = false
+		for i := 0; i < len(ring) && len(ring) >= 3; i++ {
+			a, b, c := ring[(i+len(ring)-1)%len(ring)], ring[i], ring[(i+1)%len(ring)]
+			area := (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+			if math.Abs(area) <= 1e-12*math.Hypot(b.x-a.x, b.y-a.y)*math.Hypot(c.x-a.x, c.y-a.y) {
+				ring = append(ring[:i], ring[i+1:]...)
+				changed = true
+			}
+		}
+	}
+	return ring
+}
+
+// ringEdges flatten closed subpath, keep index of segment for each edge
+func ringEdges(sp subpath, tolerance float32) (res []edge) {
+	for i, g := range sp.ring() {
+		from := g.from
+		for _, to := range g.flatten(tolerance) {
+			if from != to {
+				res = append(res, edge{a: toPoint(from), b: toPoint(to), seg: i})
+			}
+			from = to
+		}
+	}
+	return res
+}
+
+// eachOverlap call fn for every pair of edges, which bounding boxes overlap.
+// fn is called with a < b
+func eachOverlap(edges []edge, fn func(a, b int)) {
+	order := make([]int, len(edges))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return math.Min(edges[order[i]].a.x, edges[order[i]].b.x) < math.Min(edges[order[j]].a.x, edges[order[j]].b.x)
+	})
+	for i, a := range order {
+		ea := edges[a]
+		maxX := math.Max(ea.a.x, ea.b.x)
+		minY, maxY := math.Min(ea.a.y, ea.b.y), math.Max(ea.a.y, ea.b.y)
+		for _, b := range order[i+1:] {
+			eb := edges[b]
+			if math.Min(eb.a.x, eb.b.x) > maxX {
+				break
+			}
+			if math.Max(eb.a.y, eb.b.y) < minY || math.Min(eb.a.y, eb.b.y) > maxY {
+				continue
+			}
+			if a < b {
+				fn(a, b)
+			} else {
+				fn(b, a)
+			}
+		}
+	}
+}
+
+// edgeIntersect return first common point of two edges
+func edgeIntersect(e, f edge) (point, bool) {
+	ts, _ := splitParams(e, f, extent([]edge{e, f})*1e-9)
+	if len(ts) == 0 {
+		return point{}, false
+	}
+	sort.Float64s(ts)
+	return e.at(ts[0]), true
+}
+
+// splitParams return parameters on e and f where they meet each other
+func splitParams(e, f edge, eps float64) (te, tf []float64) {
+	r := point{e.b.x - e.a.x, e.b.y - e.a.y}
+	s := point{f.b.x - f.a.x, f.b.y - f.a.y}
+	q := point{f.a.x - e.a.x, f.a.y - e.a.y}
+	den := r.x*s.y - r.y*s.x
+	if math.Abs(den) > eps*eps {
+		t := (q.x*s.y - q.y*s.x) / den
+		u := (q.x*r.y - q.y*r.x) / den
+		if t >= 0 && t <= 1 && u >= 0 && u <= 1 {
+			return []float64{t}, []float64{u}
+		}
+		return nil, nil
+	}
+	// parallel, split only when they are on same line
+	if math.Abs(q.x*r.y-q.y*r.x) > eps*math.Hypot(r.x, r.y) {
+		return nil, nil
+	}
+	project := func(on edge, p point) (float64, bool) {
+		dx, dy := on.b.x-on.a.x, on.b.y-on.a.y
+		t := ((p.x-on.a.x)*dx + (p.y-on.a.y)*dy) / (dx*dx + dy*dy)
+		return t, t >= 0 && t <= 1
+	}
+	for _, p := range []point{f.a, f.b} {
+		if t, ok := project(e, p); ok {
+			te = append(te, t)
+		}
+	}
+	for _, p := range []point{e.a, e.b} {
+		if t, ok := project(f, p); ok {
+			tf = append(tf, t)
+		}
+	}
+	return te, tf
+}
+
+func extent(edges []edge) float64 {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, e := range edges {
+		for _, p := range []point{e.a, e.b} {
+			minX, minY = math.Min(minX, p.x), math.Min(minY, p.y)
+			maxX, maxY = math.Max(maxX, p.x), math.Max(maxY, p.y)
+		}
+	}
+	return math.Max(math.Max(maxX-minX, maxY-minY), 1)
+}
+
+func (e edge) at(t float64) point {
+	switch t {
+	case 0:
+		return e.a
+	case 1:
+		return e.b
+	}
+	return point{e.a.x + (e.b.x-e.a.x)*t, e.a.y + (e.b.y-e.a.y)*t}
+}
+
+func (p point) vec2() mgl32.Vec2 {
+	return mgl32.Vec2{float32(p.x), float32(p.y)}
+}
+
+func toPoint(v mgl32.Vec2) point {
+	return point{float64(v[0]), float64(v[1])}
+}
+
+func toPoints(vs []mgl32.Vec2) []point {
+	res := make([]point, len(vs))
+	for i, v := range vs {
+		res[i] = toPoint(v)
+	}
+	return res
+}
+
+// ringsElems convert closed polygons to path
+func ringsElems(rings [][]point) (res []Elem) {
+	for _, r := range rings {
+		res = append(res, MoveToAbs{To: r[0].vec2()})
+		for _, p := range r[1:] {
+			res = append(res, LineToAbs{To: p.vec2()})
+		}
+		res = append(res, ClosePath{})
+	}
+	return res
+}
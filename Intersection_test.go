@@ -0,0 +1,89 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_SelfIntersections(t *testing.T) {
+	r, err := NewRendererFromReader(strings.NewReader("M0,0 L10,10 L10,0 L0,10 Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := r.SelfIntersections(0)
+	if len(res) != 1 {
+		t.Fatal("bowtie must have 1 intersection, but", len(res))
+	}
+	if !res[0].Point.ApproxEqual(mgl32.Vec2{5, 5}) {
+		t.Error("intersection must be (5, 5), but", res[0].Point)
+	}
+
+	square, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z"))
+	if square.IsSelfIntersecting(0) {
+		t.Error("square is not self intersecting")
+	}
+}
+
+func TestRenderer_ResolveSelfIntersections(t *testing.T) {
+	r, _ := NewRendererFromReader(strings.NewReader("M0,0 L10,10 L10,0 L0,10 Z"))
+	res := NewRenderer(r.ResolveSelfIntersections(NonZero, 0)...)
+	if res.IsSelfIntersecting(0) {
+		t.Error("resolved path must not intersect itself")
+	}
+	if n := len(res.subpaths()); n != 2 {
+		t.Error("bowtie must be resolved to 2 triangles, but", n)
+	}
+
+	// hole is not connected to outer square
+	holed, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z M2,2 V8 H8 V2 Z"))
+	res = NewRenderer(holed.ResolveSelfIntersections(NonZero, 0)...)
+	if n := len(res.subpaths()); n != 2 {
+		t.Error("square with hole must be 2 contours, but", n)
+	}
+	if a := res.Area(); !mgl32.FloatEqualThreshold(a, 64, 1e-4) {
+		t.Error("square with hole must have area 64, but", a)
+	}
+
+	// even-odd star has pentagon hole
+	star, _ := NewRendererFromReader(strings.NewReader("M50,0 L79,90 L2,35 L98,35 L21,90 Z"))
+	if n := len(NewRenderer(star.ResolveSelfIntersections(EvenOdd, 0)...).subpaths()); n != 5 {
+		t.Error("even-odd star must be 5 triangles, but", n)
+	}
+	if n := len(NewRenderer(star.ResolveSelfIntersections(NonZero, 0)...).subpaths()); n != 1 {
+		t.Error("nonzero star must be 1 contour, but", n)
+	}
+}
+
+func TestRenderer_SelfIntersectionsTouching(t *testing.T) {
+	// first subpath touches itself on (10, 10), second one crosses itself on (35, 5)
+	r, err := NewRendererFromReader(strings.NewReader("M0,0 L10,10 L20,0 L20,20 L10,10 L0,20 Z M30,0 L40,10 L40,0 L30,10 Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := r.SelfIntersections(0)
+	if len(res) != 2 {
+		t.Fatal("must have 2 intersections, but", res)
+	}
+	if res[0].Subpath != 0 || res[0].Segments != [2]int{0, 3} || !res[0].Point.ApproxEqual(mgl32.Vec2{10, 10}) {
+		t.Error("touching vertex must be reported once, but", res[0])
+	}
+	if res[1].Subpath != 1 || !res[1].Point.ApproxEqualThreshold(mgl32.Vec2{35, 5}, 1e-4) {
+		t.Error("crossing must be (35, 5), but", res[1])
+	}
+}
+
+func TestRenderer_SelfIntersectionsBacktrack(t *testing.T) {
+	// second line goes back on first one
+	r, err := NewRendererFromReader(strings.NewReader("M0,0 L10,0 L5,0 M20,0 L30,0 L20,10"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := r.SelfIntersections(0)
+	if len(res) != 1 {
+		t.Fatal("must have 1 intersection, but", res)
+	}
+	if res[0].Subpath != 0 || res[0].Segments != [2]int{0, 1} || !res[0].Point.ApproxEqual(mgl32.Vec2{5, 0}) {
+		t.Error("back track must be reported on (5, 0), but", res[0])
+	}
+}
@@ -0,0 +1,449 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"math"
+	"sort"
+)
+
+// Resolution independent curve rendering, Loop and Blinn 2005
+// https://developer.nvidia.com/gpugems/gpugems3/part-iv-image-effects/chapter-25-rendering-vector-art-gpu
+type CurveMesh struct {
+	Vertices []mgl32.Vec2
+	// (k, l, m) of each vertex, fragment must be discarded when k*k*k - l*m > 0
+	Coords  []mgl32.Vec3
+	Indices []uint32
+	// Triangles between curves, always filled
+	Interior *Mesh
+}
+
+// Loop-Blinn classification of cubic
+const (
+	cubicLine = iota
+	cubicQuadratic
+	cubicSerpentine
+	cubicLoop
+	cubicCusp
+	// one inflection point is at infinity
+	cubicInfinity
+)
+
+// Parameters nearer than this to end of curve do not make subdivision
+const curveSplitMargin = 1e-4
+
+// LoopBlinn build triangles of curves with Loop-Blinn coordinates, and interior triangles.
+// Cubics are subdivided on inflection points and double points, arcs are converted to cubics,
+// and curves are subdivided until their convex hulls do not overlap,
+// curves crossing each other keep small overlap around the crossing.
+// Coordinates are oriented so that inside is the area filled by 'rule'.
+// 'tolerance' is used to decide which side of each curve is filled
+func (s *Renderer) LoopBlinn(rule FillRule, tolerance float32) *CurveMesh {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	sps := s.subpaths()
+	var edges []edge
+	pieces := make([][]segment, len(sps))
+	for i, sp := range sps {
+		edges = append(edges, ringToEdges(toPoints(sp.flatten(tolerance/8)))...)
+		for _, g := range sp.segs {
+			pieces[i] = append(pieces[i], curvePieces(g)...)
+		}
+	}
+	pieces = separateHulls(pieces)
+	res := &CurveMesh{}
+	var interior []Elem
+	for i, sp := range sps {
+		interior = append(interior, MoveToAbs{To: sp.start})
+		for _, piece := range pieces[i] {
+			// filled side of curve
+			p, n := piece.at(.5), piece.derivative(.5)
+			if l := n.Len(); l > 0 {
+				n = mgl32.Vec2{-n[1], n[0]}.Mul(tolerance / 2 / l)
+			}
+			left := rule.filled(winding(edges, toPoint(p.Add(n))))
+			right := rule.filled(winding(edges, toPoint(p.Sub(n))))
+			for _, p := range res.curve(piece, left, right) {
+				interior = append(interior, LineToAbs{To: p})
+			}
+		}
+		interior = append(interior, ClosePath{})
+	}
+	// interior polygon may cross itself, where control points are used
+	res.Interior = NewRenderer(NewRenderer(interior...).ResolveSelfIntersections(rule, tolerance)...).Triangulate(NonZero, tolerance)
+	return res
+}
+
+// curvePieces split segment to curves which have no inflection and no loop
+func curvePieces(g segment) (res []segment) {
+	switch g.kind {
+	case seg.ARC_ABS:
+		return g.cubics()
+	case seg.CURVETO_CUBIC_ABS:
+		_, _, _, splits := cubicClassify(g)
+		var last float32
+		for _, t := range splits {
+			a, b := g.split((t - last) / (1 - last))
+			res = append(res, a)
+			g, last = b, t
+		}
+		return append(res, g)
+	}
+	return []segment{g}
+}
+
+// separateHulls subdivide curves until their convex hulls do not overlap each other,
+// overlapping triangles would be filled by wrong coordinates.
+func separateHulls(pieces [][]segment) [][]segment {
+	const maxRound = 8
+	for round := 0; round < maxRound; round++ {
+		type ref struct{ sp, i int }
+		var (
+			refs  []ref
+			hulls [][]point
+		)
+		for sp := range pieces {
+			for i, g := range pieces[sp] {
+				if g.kind == seg.CURVETO_QUADRATIC_ABS || g.kind == seg.CURVETO_CUBIC_ABS {
+					refs = append(refs, ref{sp, i})
+					hulls = append(hulls, convexHull(g))
+				}
+			}
+		}
+		split := make(map[ref]bool)
+		for a := range refs {
+			for b := a + 1; b < len(refs); b++ {
+				if !hullsOverlap(hulls[a], hulls[b]) {
+					continue
+				}
+				if polygonArea(hulls[a]) > polygonArea(hulls[b]) {
+					split[refs[a]] = true
+				} else {
+					split[refs[b]] = true
+				}
+			}
+		}
+		if len(split) == 0 {
+			break
+		}
+		for sp := range pieces {
+			var next []segment
+			for i, g := range pieces[sp] {
+				if split[ref{sp, i}] {
+					a, b := g.split(.5)
+					next = append(next, a, b)
+				} else {
+					next = append(next, g)
+				}
+			}
+			pieces[sp] = next
+		}
+	}
+	return pieces
+}
+
+// convexHull of control points in counter clockwise of y-up coordinate
+func convexHull(g segment) []point {
+	ps := []point{toPoint(g.from), toPoint(g.p0), toPoint(g.to)}
+	if g.kind == seg.CURVETO_CUBIC_ABS {
+		ps = append(ps, toPoint(g.p1))
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].x < ps[j].x || (ps[i].x == ps[j].x && ps[i].y < ps[j].y)
+	})
+	// monotone chain
+	var res []point
+	for _, pass := range [][]point{ps, reversedPoints(ps)} {
+		start := len(res)
+		for _, p := range pass {
+			for len(res) >= start+2 && orient(res[len(res)-2], res[len(res)-1], p) <= 0 {
+				res = res[:len(res)-1]
+			}
+			res = append(res, p)
+		}
+		res = res[:len(res)-1]
+	}
+	return res
+}
+
+// hullsOverlap report two convex polygons share area, touching is not overlap
+func hullsOverlap(a, b []point) bool {
+	if len(a) < 3 || len(b) < 3 {
+		return false
+	}
+	separated := func(a, b []point) bool {
+		for i := range a {
+			p, q := a[i], a[(i+1)%len(a)]
+			scale := math.Hypot(q.x-p.x, q.y-p.y) * 1e-9
+			out := true
+			for _, r := range b {
+				if orient(p, q, r) > scale*math.Hypot(r.x-p.x, r.y-p.y) {
+					out = false
+					break
+				}
+			}
+			if out {
+				return true
+			}
+		}
+		return false
+	}
+	return !separated(a, b) && !separated(b, a)
+}
+
+func polygonArea(ps []point) (res float64) {
+	for i, p := range ps {
+		q := ps[(i+1)%len(ps)]
+		res += p.x*q.y - q.x*p.y
+	}
+	return math.Abs(res) / 2
+}
+
+func reversedPoints(ps []point) []point {
+	res := make([]point, len(ps))
+	for i, p := range ps {
+		res[len(ps)-1-i] = p
+	}
+	return res
+}
+
+// curve append triangles of curve, return vertices of interior polygon after g.from.
+// left and right tell which side of curve is filled
+func (s *CurveMesh) curve(g segment, left, right bool) []mgl32.Vec2 {
+	var (
+		pts    []mgl32.Vec2
+		coords [][3]float64
+	)
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		pts = []mgl32.Vec2{g.from, g.p0, g.to}
+		coords = [][3]float64{{0, 0, 0}, {.5, 0, .5}, {1, 1, 1}}
+	case seg.CURVETO_CUBIC_ABS:
+		pts = []mgl32.Vec2{g.from, g.p0, g.p1, g.to}
+		var ok bool
+		if coords, ok = cubicKLM(g); !ok {
+			return []mgl32.Vec2{g.to}
+		}
+	default:
+		return []mgl32.Vec2{g.to}
+	}
+	if !left && !right {
+		return []mgl32.Vec2{g.to}
+	}
+	var (
+		tri  [3]int
+		best float32
+	)
+	for _, t := range [][3]int{{0, 1, 2}, {0, 1, 3}, {0, 2, 3}, {1, 2, 3}} {
+		if t[2] >= len(pts) {
+			continue
+		}
+		if a := abs32(cross(pts[t[1]].Sub(pts[t[0]]), pts[t[2]].Sub(pts[t[0]]))); a > best {
+			tri, best = t, a
+		}
+	}
+	if best == 0 {
+		return []mgl32.Vec2{g.to}
+	}
+	switch {
+	case left && right:
+		// both side is filled, whole hull is inside
+		for i := range coords {
+			coords[i] = [3]float64{0, 1, 1}
+		}
+	default:
+		// orient, so that k^3 - lm < 0 on filled side
+		normal := g.derivative(.5)
+		normal = mgl32.Vec2{-normal[1], normal[0]}
+		if !left {
+			normal = normal.Mul(-1)
+		}
+		size := max32(g.to.Sub(g.from).Len(), best/g.to.Sub(g.from).Len())
+		if l := normal.Len(); l > 0 {
+			normal = normal.Mul(size * 1e-3 / l)
+		}
+		if klmAt(pts, coords, tri, g.at(.5).Add(normal)) > 0 {
+			for i := range coords {
+				coords[i][0], coords[i][1] = -coords[i][0], -coords[i][1]
+			}
+		}
+	}
+	// triangles of convex hull
+	base := uint32(len(s.Vertices))
+	for i, p := range pts {
+		s.Vertices = append(s.Vertices, p)
+		s.Coords = append(s.Coords, mgl32.Vec3{float32(coords[i][0]), float32(coords[i][1]), float32(coords[i][2])})
+	}
+	for _, t := range hullTriangles(pts) {
+		s.Indices = append(s.Indices, base+uint32(t[0]), base+uint32(t[1]), base+uint32(t[2]))
+	}
+	// control points are on filled side of chord, interior polygon must go through them
+	var side float32
+	for _, p := range pts[1 : len(pts)-1] {
+		side += cross(g.to.Sub(g.from), p.Sub(g.from))
+	}
+	if side != 0 && (side > 0) == left && left != right {
+		return pts[1:]
+	}
+	return []mgl32.Vec2{g.to}
+}
+
+// klmAt evaluate k^3 - lm on p, interpolating coords on triangle of pts
+func klmAt(pts []mgl32.Vec2, coords [][3]float64, tri [3]int, p mgl32.Vec2) float64 {
+	a, b, c := toPoint(pts[tri[0]]), toPoint(pts[tri[1]]), toPoint(pts[tri[2]])
+	q := toPoint(p)
+	area := orient(a, b, c)
+	wa, wb, wc := orient(q, b, c)/area, orient(a, q, c)/area, orient(a, b, q)/area
+	var klm [3]float64
+	for i := range klm {
+		klm[i] = wa*coords[tri[0]][i] + wb*coords[tri[1]][i] + wc*coords[tri[2]][i]
+	}
+	return klm[0]*klm[0]*klm[0] - klm[1]*klm[2]
+}
+
+// hullTriangles triangulate convex hull of 3 or 4 points
+func hullTriangles(pts []mgl32.Vec2) [][3]int {
+	if len(pts) == 3 {
+		return [][3]int{{0, 1, 2}}
+	}
+	ps := toPoints(pts)
+	inside := func(i, a, b, c int) bool {
+		o := orient(ps[a], ps[b], ps[c])
+		return o != 0 && orient(ps[a], ps[b], ps[i])*o >= 0 && orient(ps[b], ps[c], ps[i])*o >= 0 && orient(ps[c], ps[a], ps[i])*o >= 0
+	}
+	for i, t := range [][3]int{{1, 2, 3}, {0, 2, 3}, {0, 1, 3}, {0, 1, 2}} {
+		if inside(i, t[0], t[1], t[2]) {
+			return [][3]int{t}
+		}
+	}
+	// convex quadrilateral, find order around center
+	var cx, cy float64
+	for _, p := range ps {
+		cx, cy = cx+p.x/4, cy+p.y/4
+	}
+	order := []int{0, 1, 2, 3}
+	sort.Slice(order, func(i, j int) bool {
+		return math.Atan2(ps[order[i]].y-cy, ps[order[i]].x-cx) < math.Atan2(ps[order[j]].y-cy, ps[order[j]].x-cx)
+	})
+	return [][3]int{{order[0], order[1], order[2]}, {order[0], order[2], order[3]}}
+}
+
+// cubicPower return power basis of cubic, B(t) = a t^3 + b t^2 + c t + d,
+// normalized to unit size for numerical stability
+func cubicPower(g segment) (a, b, c point) {
+	p0, p1, p2, p3 := toPoint(g.from), toPoint(g.p0), toPoint(g.p1), toPoint(g.to)
+	size := 0.
+	for _, p := range []point{p1, p2, p3} {
+		size = math.Max(size, math.Max(math.Abs(p.x-p0.x), math.Abs(p.y-p0.y)))
+	}
+	if size == 0 {
+		size = 1
+	}
+	f := func(w0, w1, w2, w3 float64) point {
+		return point{
+			(w0*p0.x + w1*p1.x + w2*p2.x + w3*p3.x) / size,
+			(w0*p0.y + w1*p1.y + w2*p2.y + w3*p3.y) / size,
+		}
+	}
+	return f(-1, 3, -3, 1), f(3, -6, 3, 0), f(-3, 3, 0, 0)
+}
+
+// cubicClassify return kind of cubic and its roots.
+// Roots are inflection parameters for serpentine, cusp and infinity,
+// double point parameters for loop.
+// splits are parameters in (0, 1) where curve must be divided
+func cubicClassify(g segment) (kind int, t0, t1 float64, splits []float32) {
+	const eps = 1e-9
+	a, b, c := cubicPower(g)
+	cr := func(u, v point) float64 { return u.x*v.y - u.y*v.x }
+	ab, ac, bc := cr(a, b), cr(a, c), cr(b, c)
+	// inflection points are roots of 3(a×b)t^2 + 3(a×c)t + b×c
+	switch {
+	case math.Abs(ab) > eps:
+		disc := 9*ac*ac - 12*ab*bc
+		switch {
+		case disc > eps:
+			sq := math.Sqrt(disc)
+			kind, t0, t1 = cubicSerpentine, (-3*ac-sq)/(6*ab), (-3*ac+sq)/(6*ab)
+		case disc < -eps:
+			// double point B(t0) = B(t1), t0 + t1 = sigma, t0 * t1 = pi
+			sigma := -ac / ab
+			pi := sigma*sigma + ((a.x*b.x+a.y*b.y)*sigma+a.x*c.x+a.y*c.y)/(a.x*a.x+a.y*a.y)
+			sq := math.Sqrt(math.Max(sigma*sigma-4*pi, 0))
+			kind, t0, t1 = cubicLoop, (sigma-sq)/2, (sigma+sq)/2
+		default:
+			kind = cubicCusp
+			t0 = -ac / (2 * ab)
+			t1 = t0
+		}
+	case math.Abs(ac) > eps:
+		kind = cubicInfinity
+		t0 = -bc / (3 * ac)
+		t1 = t0
+	case math.Abs(bc) > eps:
+		return cubicQuadratic, 0, 0, nil
+	default:
+		return cubicLine, 0, 0, nil
+	}
+	for _, t := range []float64{math.Min(t0, t1), math.Max(t0, t1)} {
+		if t > curveSplitMargin && t < 1-curveSplitMargin && (len(splits) == 0 || float64(splits[0]) != t) {
+			splits = append(splits, float32(t))
+		}
+	}
+	// loop itself is closed, divide it again not to have zero chord
+	if kind == cubicLoop && len(splits) == 2 {
+		splits = []float32{splits[0], (splits[0] + splits[1]) / 2, splits[1]}
+	}
+	return kind, t0, t1, splits
+}
+
+// cubicKLM return Loop-Blinn coordinates of 4 control points.
+// k, l, m are linear functionals, so value on control point is bezier coefficient
+// of k(t), l(t), m(t) which are made of factors (t - t0) and (t - t1)
+func cubicKLM(g segment) ([][3]float64, bool) {
+	kind, t0, t1, _ := cubicClassify(g)
+	// polynomial of degree 3, power basis
+	type poly [4]float64
+	mul := func(a, b poly) (res poly) {
+		for i := range a {
+			for j := range b {
+				if i+j < 4 {
+					res[i+j] += a[i] * b[j]
+				}
+			}
+		}
+		return res
+	}
+	// root factor, scaled to keep coefficients small
+	factor := func(t float64) poly {
+		if math.Abs(t) > 1 {
+			return poly{-1, 1 / t}
+		}
+		return poly{-t, 1}
+	}
+	var k, l, m poly
+	switch kind {
+	case cubicSerpentine, cubicCusp:
+		L, M := factor(t0), factor(t1)
+		k, l, m = mul(L, M), mul(mul(L, L), L), mul(mul(M, M), M)
+	case cubicLoop:
+		L, M := factor(t0), factor(t1)
+		k, l, m = mul(L, M), mul(mul(L, L), M), mul(mul(L, M), M)
+	case cubicInfinity:
+		L := factor(t0)
+		k, l, m = L, mul(mul(L, L), L), poly{1}
+	case cubicQuadratic:
+		k, l, m = poly{0, 1}, poly{0, 0, 1}, poly{0, 1}
+	default:
+		return nil, false
+	}
+	res := make([][3]float64, 4)
+	for i, p := range []poly{k, l, m} {
+		res[0][i] = p[0]
+		res[1][i] = p[0] + p[1]/3
+		res[2][i] = p[0] + 2*p[1]/3 + p[2]/3
+		res[3][i] = p[0] + p[1] + p[2] + p[3]
+	}
+	return res, true
+}
@@ -0,0 +1,78 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+// covered report p is drawn by mesh, as fragment shader does
+func (s *CurveMesh) covered(p mgl32.Vec2) bool {
+	inside := func(a, b, c mgl32.Vec2) bool {
+		o := cross(b.Sub(a), c.Sub(a))
+		return o != 0 && cross(b.Sub(a), p.Sub(a))*o >= 0 && cross(c.Sub(b), p.Sub(b))*o >= 0 && cross(a.Sub(c), p.Sub(c))*o >= 0
+	}
+	m := s.Interior
+	for i := 0; i < len(m.Indices); i += 3 {
+		if inside(m.Vertices[m.Indices[i]], m.Vertices[m.Indices[i+1]], m.Vertices[m.Indices[i+2]]) {
+			return true
+		}
+	}
+	for i := 0; i < len(s.Indices); i += 3 {
+		tri := []mgl32.Vec2{s.Vertices[s.Indices[i]], s.Vertices[s.Indices[i+1]], s.Vertices[s.Indices[i+2]]}
+		if !inside(tri[0], tri[1], tri[2]) {
+			continue
+		}
+		var coords [][3]float64
+		for _, j := range s.Indices[i : i+3] {
+			c := s.Coords[j]
+			coords = append(coords, [3]float64{float64(c[0]), float64(c[1]), float64(c[2])})
+		}
+		if klmAt(tri, coords, [3]int{0, 1, 2}, p) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRenderer_LoopBlinn(t *testing.T) {
+	for _, src := range []string{
+		"M50,10 A40,40 0 0 1 50,90 A40,40 0 0 1 50,10 Z M50,30 A20,20 0 0 0 50,70 A20,20 0 0 0 50,30 Z",
+		"M10,50 C10,-20 90,120 90,50 C90,90 10,90 10,50 Z",
+		"M10,10 Q50,90 90,10 Q50,50 10,10 Z",
+		"M20,80 C120,0 -20,0 80,80 Z",
+	} {
+		r, _ := NewRendererFromReader(strings.NewReader(src))
+		cm := r.LoopBlinn(NonZero, 0.01)
+		var edges []edge
+		for _, sp := range r.subpaths() {
+			edges = append(edges, ringToEdges(toPoints(sp.flatten(0.001)))...)
+		}
+		var wrong int
+		for x := 1.; x < 100; x += 2 {
+		next:
+			for y := 1.; y < 100; y += 2 {
+				p := point{x, y}
+				// pixels on edge are not tested
+				for _, e := range edges {
+					if distanceToEdge(e, p) < 0.5 {
+						continue next
+					}
+				}
+				if cm.covered(p.vec2()) != (winding(edges, p) != 0) {
+					wrong++
+				}
+			}
+		}
+		if wrong > 0 {
+			t.Error(src, "has", wrong, "wrong pixels")
+		}
+	}
+}
+
+func distanceToEdge(e edge, p point) float64 {
+	dx, dy := e.b.x-e.a.x, e.b.y-e.a.y
+	t := math.Max(0, math.Min(1, ((p.x-e.a.x)*dx+(p.y-e.a.y)*dy)/(dx*dx+dy*dy)))
+	return math.Hypot(e.a.x+dx*t-p.x, e.a.y+dy*t-p.y)
+}
@@ -0,0 +1,224 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"image"
+	"math"
+)
+
+// Channels of edge color, edge is used for channel when its color has the bit
+const (
+	edgeRed uint8 = 1 << iota
+	edgeGreen
+	edgeBlue
+	edgeCyan    = edgeGreen | edgeBlue
+	edgeMagenta = edgeRed | edgeBlue
+	edgeYellow  = edgeRed | edgeGreen
+	edgeWhite   = edgeRed | edgeGreen | edgeBlue
+)
+
+// Directions meeting with bigger angle than this are corner, same as msdfgen default
+const cornerAngle = 3
+
+// coloredSegment is segment with edge color and filled side
+type coloredSegment struct {
+	distanceSegment
+	color uint8
+	// 1 when filled side is left of direction, -1 when right
+	side float32
+}
+
+// MultiDistanceField compute signed pseudo distance of each channel, 3 floats (r, g, b) per pixel.
+// Edges of every subpath are colored so that 2 edges meeting at corner share only one channel,
+// and median of channels reconstructs the sharp corner.
+// Pixels, where median disagrees with inside test by 'rule', are replaced by true distance,
+// so overlapping subpaths are also handled.
+// 'transform' and the result are same with DistanceField
+func (s *Renderer) MultiDistanceField(width, height int, transform mgl32.Mat3, rule FillRule) []float32 {
+	contours := s.distanceContours(transform)
+	edges := fieldEdges(contours)
+	inside := insideMask(edges, width, height, rule)
+	var segs []coloredSegment
+	for _, contour := range contours {
+		segs = append(segs, colorContour(contour)...)
+	}
+	// filled side of each segment by local winding
+	for i, g := range segs {
+		p, n := g.at(.5), g.direction(.5)
+		n = mgl32.Vec2{-n[1], n[0]}.Mul(4 * fieldTolerance)
+		left := rule.filled(winding(edges, toPoint(p.Add(n))))
+		right := rule.filled(winding(edges, toPoint(p.Sub(n))))
+		switch {
+		case left && !right:
+			segs[i].side = 1
+		case right && !left:
+			segs[i].side = -1
+		}
+	}
+	res := make([]float32, width*height*3)
+	dists, params := make([]float32, len(segs)), make([]float32, len(segs))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := mgl32.Vec2{float32(x) + .5, float32(y) + .5}
+			// nearest segment of each channel
+			var best [3]int
+			var bestDist, bestDot [3]float32
+			for c := range best {
+				best[c], bestDist[c], bestDot[c] = -1, float32(math.Inf(1)), 1
+			}
+			for i, g := range segs {
+				if g.boxDistance(p) > max32(bestDist[0], max32(bestDist[1], bestDist[2])) {
+					dists[i] = float32(math.Inf(1))
+					continue
+				}
+				dists[i], params[i] = g.nearest(p)
+				dot := g.orthogonality(p, params[i])
+				for c := range best {
+					if g.color&(1<<uint(c)) == 0 {
+						continue
+					}
+					if d := dists[i]; d < bestDist[c] || (d == bestDist[c] && dot < bestDot[c]) {
+						best[c], bestDist[c], bestDot[c] = i, d, dot
+					}
+				}
+			}
+			o := (y*width + x) * 3
+			var ch [3]float32
+			for c, i := range best {
+				if i < 0 {
+					ch[c] = float32(math.Inf(-1))
+					continue
+				}
+				ch[c] = segs[i].pseudoDistance(p, params[i], dists[i])
+			}
+			if in := inside[y*width+x]; (median(ch[0], ch[1], ch[2]) > 0) != in {
+				d := float32(math.Inf(1))
+				for _, gd := range dists {
+					d = min32(d, gd)
+				}
+				if !in {
+					d = -d
+				}
+				ch = [3]float32{d, d, d}
+			}
+			copy(res[o:o+3], ch[:])
+		}
+	}
+	return res
+}
+
+// MSDF render MultiDistanceField to RGBA image, outline is 128 (0.5) of median of r, g and b.
+// 'spread' is width of distance range in pixels, mapped to [0, 255]. Alpha is always 255
+func (s *Renderer) MSDF(width, height int, transform mgl32.Mat3, spread float32, rule FillRule) *image.RGBA {
+	res := image.NewRGBA(image.Rect(0, 0, width, height))
+	field := s.MultiDistanceField(width, height, transform, rule)
+	for i := 0; i < width*height; i++ {
+		o := (i/width)*res.Stride + i%width*4
+		for c := 0; c < 3; c++ {
+			res.Pix[o+c] = distanceByte(field[i*3+c], spread)
+		}
+		res.Pix[o+3] = 255
+	}
+	return res
+}
+
+func median(a, b, c float32) float32 {
+	return max32(min32(a, b), min32(max32(a, b), c))
+}
+
+// colorContour assign edge color to segments of contour.
+// Smooth contour is white, contour with one corner is split to 3 colors,
+// otherwise color changes at every corner
+func colorContour(contour []distanceSegment) (res []coloredSegment) {
+	for _, g := range contour {
+		if g.from != g.to || g.kind != seg.LINETO_ABS {
+			res = append(res, coloredSegment{distanceSegment: g, color: edgeWhite})
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	var corners []int
+	threshold := float32(math.Sin(cornerAngle))
+	for i, g := range res {
+		prev := res[(i+len(res)-1)%len(res)]
+		a, b := prev.direction(1).Normalize(), g.direction(0).Normalize()
+		if a.Dot(b) <= 0 || abs32(cross(a, b)) > threshold {
+			corners = append(corners, i)
+		}
+	}
+	switch len(corners) {
+	case 0:
+		return res
+	case 1:
+		// teardrop, needs at least 3 segments to color
+		for len(res) < 3 {
+			var split []coloredSegment
+			for _, g := range res {
+				a, b := g.split(.5)
+				split = append(split, coloredSegment{distanceSegment: newDistanceSegment(a)}, coloredSegment{distanceSegment: newDistanceSegment(b)})
+			}
+			corners[0] *= 2
+			res = split
+		}
+		colors := [3]uint8{edgeMagenta, edgeWhite, edgeYellow}
+		for i := range res {
+			part := i * 3 / len(res)
+			res[(corners[0]+i)%len(res)].color = colors[part]
+		}
+		return res
+	}
+	colors := [3]uint8{edgeCyan, edgeMagenta, edgeYellow}
+	for k, start := range corners {
+		color := colors[k%3]
+		if k == len(corners)-1 && k%3 == 0 {
+			// last one must differ from first one
+			color = colors[1]
+		}
+		end := corners[(k+1)%len(corners)]
+		for i := start; ; {
+			res[i].color = color
+			if i = (i + 1) % len(res); i == end {
+				break
+			}
+		}
+	}
+	return res
+}
+
+// direction is tangent at t, which is never zero for non degenerate segment
+func (g segment) direction(t float32) mgl32.Vec2 {
+	if d := g.derivative(t); d.Len() > 0 {
+		return d
+	}
+	return g.at(min32(t+1e-3, 1)).Sub(g.at(max32(t-1e-3, 0)))
+}
+
+// orthogonality is |cos| of angle between tangent and p, used to choose segment at shared corner
+func (g segment) orthogonality(p mgl32.Vec2, t float32) float32 {
+	d, q := g.direction(t), p.Sub(g.at(t))
+	if d.Len() == 0 || q.Len() == 0 {
+		return 0
+	}
+	return abs32(d.Normalize().Dot(q.Normalize()))
+}
+
+// pseudoDistance is signed distance, which is measured to extended tangent line
+// when nearest point is end of segment and p is beyond it
+func (s coloredSegment) pseudoDistance(p mgl32.Vec2, t, dist float32) float32 {
+	d := s.direction(t).Normalize()
+	q := p.Sub(s.at(t))
+	sign := s.side
+	if cross(d, q) < 0 {
+		sign = -sign
+	}
+	if t == 0 || t == 1 {
+		if ts := q.Dot(d); (t == 0 && ts < 0) || (t == 1 && ts > 0) {
+			if pd := abs32(cross(d, q)); pd <= dist {
+				return sign * pd
+			}
+		}
+	}
+	return sign * dist
+}
@@ -0,0 +1,60 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_MultiDistanceField(t *testing.T) {
+	const size = 16
+	// corners must stay sharp when field is magnified by bilinear filtering
+	sample := func(field []float32, channels int, x, y float32) float32 {
+		x, y = x-.5, y-.5
+		x0, y0 := int(math.Floor(float64(x))), int(math.Floor(float64(y)))
+		fx, fy := x-float32(x0), y-float32(y0)
+		var v [3]float32
+		for c := 0; c < channels; c++ {
+			at := func(x, y int) float32 { return field[(y*size+x)*channels+c] }
+			v[c] = (at(x0, y0)*(1-fx)+at(x0+1, y0)*fx)*(1-fy) + (at(x0, y0+1)*(1-fx)+at(x0+1, y0+1)*fx)*fy
+		}
+		if channels == 1 {
+			return v[0]
+		}
+		return median(v[0], v[1], v[2])
+	}
+	wrong := func(field []float32, channels int) (res int) {
+		for y := float32(2); y < 14; y += .1 {
+			for x := float32(2); x < 14; x += .1 {
+				in := x > 4 && x < 12 && y > 4 && y < 12
+				if math.Abs(float64(x-4)) < 1e-3 || math.Abs(float64(x-12)) < 1e-3 || math.Abs(float64(y-4)) < 1e-3 || math.Abs(float64(y-12)) < 1e-3 {
+					continue
+				}
+				if (sample(field, channels, x, y) > 0) != in {
+					res++
+				}
+			}
+		}
+		return res
+	}
+	r, _ := NewRendererFromReader(strings.NewReader("M4,4 H12 V12 H4 Z"))
+	if n := wrong(r.MultiDistanceField(size, size, mgl32.Ident3(), NonZero), 3); n != 0 {
+		t.Error("msdf must keep corners, but", n, "samples are wrong")
+	}
+	if n := wrong(r.DistanceField(size, size, mgl32.Ident3(), NonZero), 1); n == 0 {
+		t.Error("sdf must round corners")
+	}
+	// reversed and with hole, median must have sign of inside
+	r, _ = NewRendererFromReader(strings.NewReader("M4,4 V12 H12 V4 Z M6,6 H10 V10 H6 Z"))
+	field := r.MultiDistanceField(size, size, mgl32.Ident3(), EvenOdd)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			o := (y*size + x) * 3
+			in := x >= 4 && x < 12 && y >= 4 && y < 12 && !(x >= 6 && x < 10 && y >= 6 && y < 10)
+			if (median(field[o], field[o+1], field[o+2]) > 0) != in {
+				t.Error("sign of pixel", x, y, "is wrong")
+			}
+		}
+	}
+}
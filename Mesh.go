@@ -0,0 +1,246 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"sort"
+)
+
+// Mesh is indexed triangle list, can be uploaded as vertex and index buffer directly
+type Mesh struct {
+	Vertices []mgl32.Vec2
+	Indices  []uint32
+	// Length along path of each vertex, only filled by Stroke
+	Distances []float32
+}
+
+type meshNode struct {
+	p point
+	// id of node in rings of one polygon
+	i uint32
+}
+
+// Triangulate fill area of path by 'rule' to triangles.
+// Every outer contour is bridged with its holes, then triangulated by ear clipping.
+// Curves are flattened with 'tolerance'
+func (s *Renderer) Triangulate(rule FillRule, tolerance float32) *Mesh {
+	res := &Mesh{}
+	for _, root := range s.Contours(rule, tolerance) {
+		root.Walk(func(c *Contour) {
+			if c.Outer {
+				res.fill(c.ring, holesOf(c))
+			}
+		})
+	}
+	return res
+}
+
+// holesOf find holes of outer contour,
+// skip contours which do not change filling
+func holesOf(c *Contour) (res [][]point) {
+	for _, child := range c.Children {
+		switch {
+		case child.Hole:
+			res = append(res, child.ring)
+		case !child.Outer:
+			res = append(res, holesOf(child)...)
+		}
+	}
+	return res
+}
+
+// fill triangulate polygon with holes and append to mesh
+func (s *Mesh) fill(outer []point, holes [][]point) {
+	poly := nodes(outer, true, 0)
+	if len(poly) < 3 {
+		return
+	}
+	var hs [][]meshNode
+	id := uint32(len(poly))
+	for _, h := range holes {
+		if n := nodes(h, false, id); len(n) >= 3 {
+			hs = append(hs, n)
+			id += uint32(len(n))
+		}
+	}
+	// leftmost hole first, so later holes can bridge to earlier ones
+	sort.SliceStable(hs, func(i, j int) bool {
+		return hs[i][leftmost(hs[i])].p.x < hs[j][leftmost(hs[j])].p.x
+	})
+	for _, h := range hs {
+		poly = bridge(poly, h)
+	}
+	// vertices of holes which are not bridged are skipped
+	index := make(map[uint32]uint32)
+	for _, n := range poly {
+		if _, ok := index[n.i]; !ok {
+			index[n.i] = uint32(len(s.Vertices))
+			s.Vertices = append(s.Vertices, n.p.vec2())
+		}
+	}
+	for _, t := range earClip(poly) {
+		s.Indices = append(s.Indices, index[poly[t[0]].i], index[poly[t[1]].i], index[poly[t[2]].i])
+	}
+}
+
+// nodes convert ring to nodes numbered from 'id' and orient it,
+// positive area for outer, negative for hole
+func nodes(ring []point, outer bool, id uint32) []meshNode {
+	res := make([]meshNode, 0, len(ring))
+	var area float64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		area += p.x*q.y - q.x*p.y
+		if len(res) > 0 && res[len(res)-1].p == p {
+			continue
+		}
+		res = append(res, meshNode{p: p, i: id + uint32(len(res))})
+	}
+	if len(res) > 1 && res[0].p == res[len(res)-1].p {
+		res = res[:len(res)-1]
+	}
+	if (area > 0) != outer {
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	}
+	return res
+}
+
+func leftmost(ring []meshNode) (res int) {
+	for i, n := range ring {
+		if n.p.x < ring[res].p.x || (n.p.x == ring[res].p.x && n.p.y < ring[res].p.y) {
+			res = i
+		}
+	}
+	return res
+}
+
+// bridge connect hole to polygon with 2 coincident edges,
+// by casting ray to left from leftmost vertex of hole
+func bridge(poly, hole []meshNode) []meshNode {
+	hi := leftmost(hole)
+	h := hole[hi].p
+	// nearest edge crossing ray
+	best, qx := -1, math.Inf(-1)
+	for i := range poly {
+		a, b := poly[i].p, poly[(i+1)%len(poly)].p
+		if a.y == b.y || (h.y-a.y)*(h.y-b.y) > 0 {
+			continue
+		}
+		x := a.x + (h.y-a.y)*(b.x-a.x)/(b.y-a.y)
+		if x <= h.x && x > qx {
+			qx = x
+			best = i
+			if b.x < a.x {
+				best = (i + 1) % len(poly)
+			}
+		}
+	}
+	if best < 0 {
+		return poly
+	}
+	// reflex vertex inside of triangle (h, q, m) may hide m from h
+	if qx != h.x {
+		m := poly[best].p
+		tan := math.Inf(1)
+		for i, n := range poly {
+			p := n.p
+			if p.x < m.x || p.x >= h.x || i == best {
+				continue
+			}
+			var a, c point
+			if h.y < m.y {
+				a, c = h, point{qx, h.y}
+			} else {
+				a, c = point{qx, h.y}, h
+			}
+			if !pointInTriangle(a, m, c, p) {
+				continue
+			}
+			t := math.Abs(h.y-p.y) / (h.x - p.x)
+			if t < tan || (t == tan && p.x > poly[best].p.x) {
+				prev, next := poly[(i+len(poly)-1)%len(poly)].p, poly[(i+1)%len(poly)].p
+				if locallyInside(prev, p, next, h) {
+					best, tan = i, t
+				}
+			}
+		}
+	}
+	res := make([]meshNode, 0, len(poly)+len(hole)+2)
+	res = append(res, poly[:best+1]...)
+	for i := range hole {
+		res = append(res, hole[(hi+i)%len(hole)])
+	}
+	res = append(res, hole[hi], poly[best])
+	return append(res, poly[best+1:]...)
+}
+
+// earClip triangulate simple polygon with positive area,
+// return indices of polygon
+func earClip(poly []meshNode) (res [][3]int) {
+	n := len(poly)
+	prev, next := make([]int, n), make([]int, n)
+	for i := range poly {
+		prev[i], next[i] = (i+n-1)%n, (i+1)%n
+	}
+	area := func(a, b, c int) float64 {
+		return orient(poly[a].p, poly[b].p, poly[c].p)
+	}
+	isEar := func(b int) bool {
+		a, c := prev[b], next[b]
+		if area(a, b, c) <= 0 {
+			return false
+		}
+		for j := next[c]; j != a; j = next[j] {
+			p := poly[j].p
+			if p == poly[a].p || p == poly[b].p || p == poly[c].p {
+				continue
+			}
+			if pointInTriangle(poly[a].p, poly[b].p, poly[c].p, p) && area(prev[j], j, next[j]) <= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	remove := func(b int) {
+		if area(prev[b], b, next[b]) > 0 {
+			res = append(res, [3]int{prev[b], b, next[b]})
+		}
+		next[prev[b]], prev[next[b]] = next[b], prev[b]
+	}
+	for i, left, stall := 0, n, 0; left >= 3; {
+		// collinear or spike vertex is removed without triangle
+		if left == 3 || area(prev[i], i, next[i]) == 0 || isEar(i) {
+			nx := next[i]
+			remove(i)
+			i, left, stall = nx, left-1, 0
+			continue
+		}
+		i, stall = next[i], stall+1
+		if stall > left {
+			// no ear by numerical error, clip anyway rather than loop forever
+			nx := next[i]
+			remove(i)
+			i, left, stall = nx, left-1, 0
+		}
+	}
+	return res
+}
+
+// orient is twice of signed area of triangle
+func orient(a, b, c point) float64 {
+	return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+}
+
+func pointInTriangle(a, b, c, p point) bool {
+	return orient(a, b, p) >= 0 && orient(b, c, p) >= 0 && orient(c, a, p) >= 0
+}
+
+// locallyInside report direction b->p is inside of polygon at vertex b, which has neighbors a and c
+func locallyInside(a, b, c, p point) bool {
+	if orient(a, b, c) > 0 {
+		return orient(b, p, c) <= 0 && orient(b, a, p) <= 0
+	}
+	return orient(b, p, a) > 0 || orient(b, c, p) > 0
+}
@@ -0,0 +1,51 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"strings"
+	"testing"
+)
+
+func meshArea(m *Mesh) (res float32) {
+	for i := 0; i < len(m.Indices); i += 3 {
+		a, b, c := m.Vertices[m.Indices[i]], m.Vertices[m.Indices[i+1]], m.Vertices[m.Indices[i+2]]
+		res += cross(b.Sub(a), c.Sub(a)) / 2
+	}
+	return res
+}
+
+func TestRenderer_Triangulate(t *testing.T) {
+	for src, expect := range map[string]float32{
+		"M0,0 H10 V10 H0 Z": 100,
+		"M0,0 H10 V10 H0 Z M2,2 H4 V4 H2 Z M6,6 V8 H8 V6 Z":                                  92,
+		"M0,0 H10 V10 H5 V2 H4 V10 H0 Z":                                                     92,
+		"M10,0 A10,10 0 0 1 -10,0 A10,10 0 0 1 10,0 Z M5,0 A5,5 0 0 1 -5,0 A5,5 0 0 1 5,0 Z": 75 * 3.14159,
+	} {
+		r, _ := NewRendererFromReader(strings.NewReader(src))
+		m := r.Triangulate(EvenOdd, 0.01)
+		if a := meshArea(m); !mgl32.FloatEqualThreshold(a, expect, expect*1e-3) {
+			t.Error(src, "must have area", expect, "but", a)
+		}
+		if len(m.Indices)%3 != 0 {
+			t.Error("indices must be multiple of 3")
+		}
+	}
+}
+
+func TestMesh_FillSkippedHoles(t *testing.T) {
+	m := &Mesh{}
+	square := []point{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	// degenerate hole and hole on left of square
+	m.fill(square, [][]point{{{2, 2}, {4, 4}}, {{-20, 2}, {-20, 4}, {-22, 4}, {-22, 2}}})
+	if len(m.Vertices) != 4 {
+		t.Error("skipped holes must not add vertices, but", m.Vertices)
+	}
+	if a := meshArea(m); !mgl32.FloatEqual(a, 100) {
+		t.Error("square must have area 100, but", a)
+	}
+	// nothing is added for degenerate outer
+	m.fill([]point{{0, 0}, {1, 1}}, nil)
+	if len(m.Vertices) != 4 {
+		t.Error("degenerate outer must not add vertices, but", m.Vertices)
+	}
+}
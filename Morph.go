@@ -0,0 +1,215 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"math"
+)
+
+// Morph interpolate between 2 paths, like SMIL animation of 'd' attribute
+type Morph struct {
+	// same commands, interpolated per element
+	from, to []Elem
+	// otherwise, normalized to cubics with same count
+	fromSubpaths, toSubpaths []subpath
+}
+
+// NewMorph prepare interpolation from 'from' to 'to'.
+// When they have same list of commands, each value of elements is interpolated.
+// Otherwise both are converted to cubics, subpaths and segments are matched by subdivision,
+// and start point of closed subpath is rotated to minimize distortion
+func NewMorph(from, to *Renderer) *Morph {
+	if compatible(from.data, to.data) {
+		return &Morph{from: from.data, to: to.data}
+	}
+	a, b := morphSubpaths(from.subpaths()), morphSubpaths(to.subpaths())
+	// missing subpath grows from center of its pair
+	for len(a) < len(b) {
+		a = append(a, pointSubpath(b[len(a)]))
+	}
+	for len(b) < len(a) {
+		b = append(b, pointSubpath(a[len(b)]))
+	}
+	for i := range a {
+		a[i].segs, b[i].segs = subdivideTo(a[i].segs, len(b[i].segs)), subdivideTo(b[i].segs, len(a[i].segs))
+		if a[i].closed && b[i].closed {
+			b[i] = alignSubpath(a[i], b[i])
+		}
+		a[i].closed = a[i].closed && b[i].closed
+	}
+	return &Morph{fromSubpaths: a, toSubpaths: b}
+}
+
+// At return path on 't', 0 is 'from' and 1 is 'to'
+func (s *Morph) At(t float32) (res []Elem) {
+	if s.from != nil || s.fromSubpaths == nil {
+		res = make([]Elem, len(s.from))
+		for i := range s.from {
+			res[i] = lerpElem(s.from[i], s.to[i], t)
+		}
+		return res
+	}
+	for i, a := range s.fromSubpaths {
+		b := s.toSubpaths[i]
+		res = append(res, MoveToAbs{To: lerp(a.start, b.start, t)})
+		for k, g := range a.segs {
+			h := b.segs[k]
+			res = append(res, CurveToCubicAbs{P0: lerp(g.p0, h.p0, t), P1: lerp(g.p1, h.p1, t), To: lerp(g.to, h.to, t)})
+		}
+		if a.closed {
+			res = append(res, ClosePath{})
+		}
+	}
+	return res
+}
+
+// compatible report both have same commands, and arcs have same flags
+func compatible(a, b []Elem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type() != b[i].Type() {
+			return false
+		}
+		switch ea := a[i].(type) {
+		case ArcAbs:
+			eb := b[i].(ArcAbs)
+			if ea.LargeArc != eb.LargeArc || ea.Sweep != eb.Sweep {
+				return false
+			}
+		case ArcRel:
+			eb := b[i].(ArcRel)
+			if ea.LargeArc != eb.LargeArc || ea.Sweep != eb.Sweep {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lerpElem interpolate every value of elements of same command
+func lerpElem(a, b Elem, t float32) Elem {
+	mix := func(x, y float32) float32 { return x + (y-x)*t }
+	switch ea := a.(type) {
+	case MoveToAbs:
+		return MoveToAbs{To: lerp(ea.To, b.(MoveToAbs).To, t)}
+	case MoveToRel:
+		return MoveToRel{To: lerp(ea.To, b.(MoveToRel).To, t)}
+	case LineToAbs:
+		return LineToAbs{To: lerp(ea.To, b.(LineToAbs).To, t)}
+	case LineToRel:
+		return LineToRel{To: lerp(ea.To, b.(LineToRel).To, t)}
+	case LineToHorizontalAbs:
+		return LineToHorizontalAbs{X: mix(ea.X, b.(LineToHorizontalAbs).X)}
+	case LineToHorizontalRel:
+		return LineToHorizontalRel{X: mix(ea.X, b.(LineToHorizontalRel).X)}
+	case LineToVerticalAbs:
+		return LineToVerticalAbs{Y: mix(ea.Y, b.(LineToVerticalAbs).Y)}
+	case LineToVerticalRel:
+		return LineToVerticalRel{Y: mix(ea.Y, b.(LineToVerticalRel).Y)}
+	case CurveToCubicAbs:
+		eb := b.(CurveToCubicAbs)
+		return CurveToCubicAbs{P0: lerp(ea.P0, eb.P0, t), P1: lerp(ea.P1, eb.P1, t), To: lerp(ea.To, eb.To, t)}
+	case CurveToCubicRel:
+		eb := b.(CurveToCubicRel)
+		return CurveToCubicRel{P0: lerp(ea.P0, eb.P0, t), P1: lerp(ea.P1, eb.P1, t), To: lerp(ea.To, eb.To, t)}
+	case CurveToCubicSmoothAbs:
+		eb := b.(CurveToCubicSmoothAbs)
+		return CurveToCubicSmoothAbs{P1: lerp(ea.P1, eb.P1, t), To: lerp(ea.To, eb.To, t)}
+	case CurveToCubicSmoothRel:
+		eb := b.(CurveToCubicSmoothRel)
+		return CurveToCubicSmoothRel{P1: lerp(ea.P1, eb.P1, t), To: lerp(ea.To, eb.To, t)}
+	case CurveToQuadraticAbs:
+		eb := b.(CurveToQuadraticAbs)
+		return CurveToQuadraticAbs{P0: lerp(ea.P0, eb.P0, t), To: lerp(ea.To, eb.To, t)}
+	case CurveToQuadraticRel:
+		eb := b.(CurveToQuadraticRel)
+		return CurveToQuadraticRel{P0: lerp(ea.P0, eb.P0, t), To: lerp(ea.To, eb.To, t)}
+	case CurveToQuadraticSmoothAbs:
+		return CurveToQuadraticSmoothAbs{To: lerp(ea.To, b.(CurveToQuadraticSmoothAbs).To, t)}
+	case CurveToQuadraticSmoothRel:
+		return CurveToQuadraticSmoothRel{To: lerp(ea.To, b.(CurveToQuadraticSmoothRel).To, t)}
+	case ArcAbs:
+		eb := b.(ArcAbs)
+		return ArcAbs{To: lerp(ea.To, eb.To, t), Radius: lerp(ea.Radius, eb.Radius, t), Angle: mix(ea.Angle, eb.Angle), LargeArc: ea.LargeArc, Sweep: ea.Sweep}
+	case ArcRel:
+		eb := b.(ArcRel)
+		return ArcRel{To: lerp(ea.To, eb.To, t), Radius: lerp(ea.Radius, eb.Radius, t), Angle: mix(ea.Angle, eb.Angle), LargeArc: ea.LargeArc, Sweep: ea.Sweep}
+	}
+	return a
+}
+
+// morphSubpaths convert subpaths to cubics, closing line of closed subpath is included
+func morphSubpaths(sps []subpath) []subpath {
+	res := make([]subpath, len(sps))
+	for i, sp := range sps {
+		res[i] = subpath{start: sp.start, closed: sp.closed}
+		for _, g := range sp.drawn() {
+			res[i].segs = append(res[i].segs, g.cubics()...)
+		}
+	}
+	return res
+}
+
+// pointSubpath is subpath collapsed to center of 'pair', with same count of segments
+func pointSubpath(pair subpath) subpath {
+	lo, hi := pair.start, pair.start
+	for _, g := range pair.segs {
+		for _, p := range []mgl32.Vec2{g.p0, g.p1, g.to} {
+			lo = mgl32.Vec2{min32(lo[0], p[0]), min32(lo[1], p[1])}
+			hi = mgl32.Vec2{max32(hi[0], p[0]), max32(hi[1], p[1])}
+		}
+	}
+	c := lerp(lo, hi, .5)
+	res := subpath{start: c, closed: pair.closed, segs: make([]segment, len(pair.segs))}
+	for i := range res.segs {
+		res.segs[i] = segment{kind: seg.CURVETO_CUBIC_ABS, from: c, p0: c, p1: c, to: c}
+	}
+	return res
+}
+
+// subdivideTo split longest segment in half until there are n segments
+func subdivideTo(segs []segment, n int) []segment {
+	segs = append([]segment(nil), segs...)
+	lengths := segmentLengths(segs)
+	for len(segs) < n {
+		longest := 0
+		for i, l := range lengths {
+			if l > lengths[longest] {
+				longest = i
+			}
+		}
+		a, b := segs[longest].split(.5)
+		segs = append(segs[:longest], append([]segment{a, b}, segs[longest+1:]...)...)
+		half := lengths[longest] / 2
+		lengths = append(lengths[:longest], append([]float32{half, half}, lengths[longest+1:]...)...)
+	}
+	return segs
+}
+
+// alignSubpath rotate, and reverse if needed, closed 'b' to have least distance to 'a' per segment
+func alignSubpath(a, b subpath) subpath {
+	best, bestDist := b, math.Inf(1)
+	for _, c := range []subpath{b, b.reverse()} {
+		if len(c.segs) != len(a.segs) {
+			continue
+		}
+		for k := range c.segs {
+			var d float64
+			for i, g := range a.segs {
+				h := c.segs[(i+k)%len(c.segs)]
+				d += float64(g.to.Sub(h.to).Len())
+			}
+			if d < bestDist {
+				best, bestDist = rotateSubpath(c, k), d
+			}
+		}
+	}
+	return best
+}
+
+func rotateSubpath(s subpath, k int) subpath {
+	segs := append(append([]segment(nil), s.segs[k:]...), s.segs[:k]...)
+	return subpath{start: segs[0].from, segs: segs, closed: s.closed}
+}
@@ -0,0 +1,35 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMorph_At(t *testing.T) {
+	a, _ := NewRendererFromReader(strings.NewReader("M0,0 L10,0 L5,10 Z"))
+	b, _ := NewRendererFromReader(strings.NewReader("M0,0 L20,0 L10,20 Z"))
+	res := NewMorph(a, b).At(.5)
+	if len(res) != 4 || res[1].(LineToAbs).To != (mgl32.Vec2{15, 0}) || res[2].(LineToAbs).To != (mgl32.Vec2{7.5, 15}) {
+		t.Error("compatible paths must be interpolated per element, but", res)
+	}
+	// same square starting on other corner keeps its shape
+	a, _ = NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z"))
+	b, _ = NewRendererFromReader(strings.NewReader("M10,10 L0,10 L0,0 L10,0 Z"))
+	if area := NewRenderer(NewMorph(a, b).At(.5)...).Area(); !mgl32.FloatEqualThreshold(area, 100, 1e-4) {
+		t.Error("aligned square must have area 100, but", area)
+	}
+	// square to circle, and extra subpath grows
+	b, _ = NewRendererFromReader(strings.NewReader("M15,5 A10,10 0 0 1 -5,5 A10,10 0 0 1 15,5 Z M20,20 H30 V30 Z"))
+	m := NewMorph(a, b)
+	if area := NewRenderer(m.At(0)...).Area(); !mgl32.FloatEqualThreshold(area, 100, 1e-4) {
+		t.Error("morph must start from square, but area is", area)
+	}
+	if area := NewRenderer(m.At(1)...).Area(); !mgl32.FloatEqualThreshold(area, 100*math.Pi+50, .5) {
+		t.Error("morph must end on circle and triangle, but area is", area)
+	}
+	if sps := NewRenderer(m.At(.5)...).subpaths(); len(sps) != 2 || len(sps[0].segs) != len(NewRenderer(m.At(0)...).subpaths()[0].segs) {
+		t.Error("segment counts must be same for all t")
+	}
+}
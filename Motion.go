@@ -0,0 +1,106 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"sort"
+)
+
+// https://www.w3.org/TR/SVG11/animate.html#RotateAttribute
+type MotionRotate uint8
+
+const (
+	// fixed Motion.Angle
+	RotateAngle MotionRotate = iota
+	RotateAuto
+	RotateAutoReverse
+)
+
+// Motion evaluate position and rotation along path like SVG <animateMotion>
+type Motion struct {
+	// Fractions of path length, at each of KeyTimes.
+	// Without KeyPoints, motion is paced along path
+	KeyPoints []float32
+	// Progress of each KeyPoints, evenly distributed if empty.
+	// SMIL treats different length from KeyPoints as error, here it is ignored as empty
+	KeyTimes []float32
+	Rotate   MotionRotate
+	// Degree, used when Rotate is RotateAngle
+	Angle float32
+	// segments of path, move to does not add length
+	segs    []segment
+	lengths []float32
+	total   float32
+}
+
+// NewMotion prepare motion along 'path', closing line of closed subpath is included
+func NewMotion(path *Renderer) *Motion {
+	res := &Motion{}
+	for _, sp := range path.subpaths() {
+		res.segs = append(res.segs, sp.drawn()...)
+	}
+	res.lengths = segmentLengths(res.segs)
+	res.total = sum32(res.lengths)
+	return res
+}
+
+// At return transform of object on 'progress' of simple duration, 0 <= progress <= 1.
+// It is translation to point on path, multiplied by rotation
+func (s *Motion) At(progress float32) mgl32.Mat3 {
+	p, tangent := s.point(s.distance(clamp01(progress)))
+	angle := mgl32.DegToRad(s.Angle)
+	switch s.Rotate {
+	case RotateAuto:
+		angle = float32(math.Atan2(float64(tangent[1]), float64(tangent[0])))
+	case RotateAutoReverse:
+		angle = float32(math.Atan2(float64(tangent[1]), float64(tangent[0])) + math.Pi)
+	}
+	return mgl32.Translate2D(p[0], p[1]).Mul3(mgl32.HomogRotate2D(angle))
+}
+
+// distance convert progress to fraction of length by KeyPoints and KeyTimes
+func (s *Motion) distance(progress float32) float32 {
+	n := len(s.KeyPoints)
+	if n == 0 {
+		return progress
+	}
+	if n == 1 {
+		return s.KeyPoints[0]
+	}
+	times := s.KeyTimes
+	// missing or mismatched, evenly distributed
+	if len(times) != n {
+		times = make([]float32, n)
+		for i := range times {
+			times[i] = float32(i) / float32(n-1)
+		}
+	}
+	i := sort.Search(n, func(i int) bool { return times[i] > progress }) - 1
+	switch {
+	case i < 0:
+		return s.KeyPoints[0]
+	case i >= n-1:
+		return s.KeyPoints[n-1]
+	}
+	span := times[i+1] - times[i]
+	if span <= 0 {
+		return s.KeyPoints[i+1]
+	}
+	return s.KeyPoints[i] + (s.KeyPoints[i+1]-s.KeyPoints[i])*(progress-times[i])/span
+}
+
+// point return position and direction on fraction of path length
+func (s *Motion) point(fraction float32) (mgl32.Vec2, mgl32.Vec2) {
+	if len(s.segs) == 0 {
+		return mgl32.Vec2{}, mgl32.Vec2{1, 0}
+	}
+	length := clamp01(fraction) * s.total
+	for i, g := range s.segs {
+		if (length <= s.lengths[i] && s.lengths[i] > 0) || i == len(s.segs)-1 {
+			t := g.paramAt(length)
+			return g.at(t), g.direction(t)
+		}
+		length -= s.lengths[i]
+	}
+	return mgl32.Vec2{}, mgl32.Vec2{1, 0}
+}
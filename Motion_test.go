@@ -0,0 +1,38 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"strings"
+	"testing"
+)
+
+func TestMotion_At(t *testing.T) {
+	path, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10"))
+	m := NewMotion(path)
+	apply := func(progress float32) mgl32.Vec2 {
+		return m.At(progress).Mul3x1(mgl32.Vec3{1, 0, 1}).Vec2()
+	}
+	if p := apply(.25); !p.ApproxEqualThreshold(mgl32.Vec2{6, 0}, 1e-3) {
+		t.Error("fixed angle must not rotate, but", p)
+	}
+	m.Rotate = RotateAuto
+	if p := apply(.75); !p.ApproxEqualThreshold(mgl32.Vec2{10, 6}, 1e-3) {
+		t.Error("auto must rotate along tangent, but", p)
+	}
+	m.Rotate = RotateAutoReverse
+	if p := apply(.75); !p.ApproxEqualThreshold(mgl32.Vec2{10, 4}, 1e-3) {
+		t.Error("auto-reverse must rotate against tangent, but", p)
+	}
+	m.Rotate, m.Angle = RotateAngle, 90
+	m.KeyPoints, m.KeyTimes = []float32{0, 1, .5}, []float32{0, .5, 1}
+	for progress, expect := range map[float32]mgl32.Vec2{0: {0, 1}, .5: {10, 11}, .75: {10, 6}, 1: {10, 1}} {
+		if p := apply(progress); !p.ApproxEqualThreshold(expect, 1e-3) {
+			t.Error("on", progress, "it must be", expect, "but", p)
+		}
+	}
+	// mismatched KeyTimes is ignored
+	m.KeyTimes = []float32{0, 1}
+	if p := apply(.25); !p.ApproxEqualThreshold(mgl32.Vec2{10, 1}, 1e-3) {
+		t.Error("mismatched key times must be evenly distributed, but", p)
+	}
+}
@@ -0,0 +1,83 @@
+package psvg
+
+import (
+	"math"
+)
+
+// Offset grow filled area of path by 'distance', or shrink it when 'distance' is negative.
+// Corners of grown side are connected by 'join', miter longer than 'miterLimit' times
+// of distance becomes bevel, zero means SVG default 4.
+// Curves are flattened with 'tolerance', and result is closed polygons without self intersection
+func (s *Renderer) Offset(distance float32, join LineJoin, miterLimit float32, rule FillRule, tolerance float32) []Elem {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	if miterLimit < 1 {
+		miterLimit = defaultMiterLimit
+	}
+	var rings [][]point
+	for _, sp := range s.subpaths() {
+		rings = append(rings, toPoints(sp.flatten(tolerance)))
+	}
+	// rings with filled area on left
+	rings = resolveRings(rings, rule.filled)
+	if distance == 0 {
+		return ringsElems(rings)
+	}
+	var offset [][]point
+	for _, r := range rings {
+		offset = append(offset, offsetRing(r, float64(distance), join, float64(miterLimit), float64(tolerance)))
+	}
+	// loops of inner corners and vanished parts have non positive winding
+	return ringsElems(resolveRings(offset, func(w int) bool { return w > 0 }))
+}
+
+// offsetRing move ring to its right side by d, ring must have filled area on its left
+func offsetRing(ring []point, d float64, join LineJoin, miterLimit, tolerance float64) (res []point) {
+	n := len(ring)
+	// step of round join
+	step := math.Pi / 2
+	if ad := math.Abs(d); tolerance < ad {
+		step = 2 * math.Acos(1-tolerance/ad)
+	}
+	normal := func(a, b point) point {
+		dx, dy := b.x-a.x, b.y-a.y
+		l := math.Hypot(dx, dy)
+		return point{dy / l * d, -dx / l * d}
+	}
+	for i, cur := range ring {
+		prev, next := ring[(i+n-1)%n], ring[(i+1)%n]
+		n0, n1 := normal(prev, cur), normal(cur, next)
+		a, b := point{cur.x + n0.x, cur.y + n0.y}, point{cur.x + n1.x, cur.y + n1.y}
+		turn := orient(prev, cur, next)
+		// offset side is inner side of corner, connect through vertex and let cleanup remove loop
+		if (turn > 0) != (d > 0) {
+			if turn != 0 {
+				res = append(res, a, cur, b)
+			} else {
+				res = append(res, a)
+			}
+			continue
+		}
+		res = append(res, a)
+		// ratio of miter length and distance is 1/cos(theta/2)
+		bx, by := n0.x+n1.x, n0.y+n1.y
+		cosHalf := math.Hypot(bx, by) / 2 / math.Abs(d)
+		// miter close to round is used for smooth points of flattened curve
+		if cosHalf > 0 && ((join == MiterJoin && 1/cosHalf <= miterLimit) || math.Abs(d)*(1/cosHalf-1) <= tolerance) {
+			l := math.Hypot(bx, by)
+			k := math.Abs(d) / cosHalf / l
+			res = append(res, point{cur.x + bx*k, cur.y + by*k})
+		} else if join == RoundJoin {
+			from := math.Atan2(n0.y, n0.x)
+			sweep := math.Atan2(n0.x*n1.y-n0.y*n1.x, n0.x*n1.x+n0.y*n1.y)
+			steps := int(math.Ceil(math.Abs(sweep) / step))
+			for k := 1; k < steps; k++ {
+				sin, cos := math.Sincos(from + sweep*float64(k)/float64(steps))
+				res = append(res, point{cur.x + cos*math.Abs(d), cur.y + sin*math.Abs(d)})
+			}
+		}
+		res = append(res, b)
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Offset(t *testing.T) {
+	square, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z"))
+	for _, c := range []struct {
+		distance float32
+		join     LineJoin
+		area     float32
+	}{
+		{2, MiterJoin, 196},
+		{2, BevelJoin, 188},
+		{2, RoundJoin, 180 + 4*math.Pi},
+		{-2, RoundJoin, 36},
+		{-6, MiterJoin, 0},
+	} {
+		res := NewRenderer(square.Offset(c.distance, c.join, 0, NonZero, .001)...)
+		if a := abs32(res.Area()); !mgl32.FloatEqualThreshold(a, c.area, 1e-2) {
+			t.Error("offset", c.distance, c.join, "must have area", c.area, "but", a)
+		}
+	}
+	// hole is shrunk, and vanishes
+	frame, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z M3,3 H7 V7 H3 Z"))
+	if res := frame.Offset(1, MiterJoin, 0, EvenOdd, .1); len(NewRenderer(res...).subpaths()) != 2 {
+		t.Error("hole must be kept", res)
+	}
+	if res := frame.Offset(3, MiterJoin, 0, EvenOdd, .1); len(NewRenderer(res...).subpaths()) != 1 {
+		t.Error("hole must vanish", res)
+	}
+	// circle stays circle
+	circle, _ := NewRendererFromReader(strings.NewReader("M10,0 A10,10 0 0 1 -10,0 A10,10 0 0 1 10,0 Z"))
+	for _, p := range NewRenderer(circle.Offset(-3, BevelJoin, 0, NonZero, .01)...).subpaths()[0].flatten(.01) {
+		if d := abs32(p.Len() - 7); d > .02 {
+			t.Error(p, "must be on circle of radius 7")
+		}
+	}
+}
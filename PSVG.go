@@ -62,7 +62,7 @@ func (s *Parser) Next() Elem {
 	}
 	//
 	for i, v := range s.buf {
-		if matchingSymbol(v) {
+		if matchingSymbol(v) && !s.exponent(i) {
 			if s.cmd == 0 {
 				s.cmd = v
 				s.buf = s.buf[i+1:]
@@ -84,6 +84,21 @@ func (s *Parser) Next() Elem {
 
 }
 
+// exponent report buf[i] is 'e' or 'E' of number like "1e-3", not a command
+func (s *Parser) exponent(i int) bool {
+	if s.buf[i] != 'e' && s.buf[i] != 'E' {
+		return false
+	}
+	var prev byte
+	switch {
+	case i > 0:
+		prev = s.buf[i-1]
+	case len(s.prv) > 0:
+		prev = s.prv[len(s.prv)-1]
+	}
+	return ('0' <= prev && prev <= '9') || prev == '.'
+}
+
 // Allways return at least 1 args
 func convert(command byte, data []byte) (res []Elem) {
 	switch command {
@@ -1,6 +1,8 @@
 package psvg
 
 import (
+	"github.com/go-gl/mathgl/mgl32"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -34,3 +36,33 @@ func TestParser(t *testing.T) {
 		i ++
 	}
 }
+
+func TestParserSeparators(t *testing.T) {
+	line := []Elem{MoveToAbs{To: mgl32.Vec2{1, -2}}, LineToAbs{To: mgl32.Vec2{-3, 40}}}
+	for src, expect := range map[string][]Elem{
+		"M1,-2L-3,40":         line,
+		"M 1 -2 L -3 40":      line,
+		"M1-2L-3+40":          line,
+		"M1 , -2\tL\n-3,\r40": line,
+		"M1e0-2L-30e-1,4E1":   line,
+		"M.1.2l.3e1.4":        {MoveToAbs{To: mgl32.Vec2{.1, .2}}, LineToRel{To: mgl32.Vec2{3, .4}}},
+		"M1,-2 L-3,40 L5":     append(line[:2:2], nil),
+		"M1,-2 L-3,40 L5,":    append(line[:2:2], nil),
+		"M1,-2 L-3,40 L":      append(line[:2:2], nil),
+		// exponent on start of second buffer
+		"M" + strings.Repeat(" ", 1022) + "1e1,3": {MoveToAbs{To: mgl32.Vec2{10, 3}}},
+	} {
+		p := NewParser(strings.NewReader(src))
+		var res []Elem
+		for e := p.Next(); e != nil; e = p.Next() {
+			// truncated command is error
+			if _, ok := e.(UnknownError); ok {
+				e = nil
+			}
+			res = append(res, e)
+		}
+		if !reflect.DeepEqual(res, expect) {
+			t.Errorf("%q must be %v, but %v", src, expect, res)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"strconv"
+	"strings"
+)
+
+// PathData serialize path to 'd' attribute, which Parser read back to same Elems.
+// UnknownCommand and UnknownError are skipped
+func PathData(elems ...Elem) string {
+	var b strings.Builder
+	write := func(cmd byte, args ...float32) {
+		b.WriteByte(cmd)
+		for i, a := range args {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			// no negative zero
+			if a == 0 {
+				a = 0
+			}
+			b.WriteString(strconv.FormatFloat(float64(a), 'f', -1, 32))
+		}
+	}
+	vec := func(vs ...mgl32.Vec2) (res []float32) {
+		for _, v := range vs {
+			res = append(res, v[0], v[1])
+		}
+		return res
+	}
+	flag := func(f bool) float32 {
+		if f {
+			return 1
+		}
+		return 0
+	}
+	for _, e := range elems {
+		switch t := e.(type) {
+		case ClosePath:
+			write('Z')
+		case MoveToAbs:
+			write('M', vec(t.To)...)
+		case MoveToRel:
+			write('m', vec(t.To)...)
+		case LineToAbs:
+			write('L', vec(t.To)...)
+		case LineToRel:
+			write('l', vec(t.To)...)
+		case LineToHorizontalAbs:
+			write('H', t.X)
+		case LineToHorizontalRel:
+			write('h', t.X)
+		case LineToVerticalAbs:
+			write('V', t.Y)
+		case LineToVerticalRel:
+			write('v', t.Y)
+		case CurveToCubicAbs:
+			write('C', vec(t.P0, t.P1, t.To)...)
+		case CurveToCubicRel:
+			write('c', vec(t.P0, t.P1, t.To)...)
+		case CurveToCubicSmoothAbs:
+			write('S', vec(t.P1, t.To)...)
+		case CurveToCubicSmoothRel:
+			write('s', vec(t.P1, t.To)...)
+		case CurveToQuadraticAbs:
+			write('Q', vec(t.P0, t.To)...)
+		case CurveToQuadraticRel:
+			write('q', vec(t.P0, t.To)...)
+		case CurveToQuadraticSmoothAbs:
+			write('T', vec(t.To)...)
+		case CurveToQuadraticSmoothRel:
+			write('t', vec(t.To)...)
+		case ArcAbs:
+			write('A', t.Radius[0], t.Radius[1], t.Angle, flag(t.LargeArc), flag(t.Sweep), t.To[0], t.To[1])
+		case ArcRel:
+			write('a', t.Radius[0], t.Radius[1], t.Angle, flag(t.LargeArc), flag(t.Sweep), t.To[0], t.To[1])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,24 @@
+package psvg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPathData(t *testing.T) {
+	for _, src := range []string{
+		"M0 0L10 -0.5H3V-4Z",
+		"m1.5 2l1 1h2v3Z",
+		"M0 0C1 2 3 4 5 6S7 8 9 10Q1 1 2 2T3 3",
+		"m0 0c1 2 3 4 5 6s7 8 9 10q1 1 2 2t3 3",
+		"M0 0A5 4 30 1 0 10 0a5 5 0 0 1 -10 0",
+	} {
+		r, err := NewRendererFromReader(strings.NewReader(src))
+		if err != nil {
+			t.Fatal(src, err)
+		}
+		if d := PathData(r.data...); d != src {
+			t.Error(src, "must be serialized same, but", d)
+		}
+	}
+}
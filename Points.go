@@ -0,0 +1,43 @@
+package psvg
+
+import (
+	"bytes"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/pkg/errors"
+)
+
+// ParsePoints parse 'points' attribute of <polyline> and <polygon>.
+// https://www.w3.org/TR/SVG2/shapes.html#DataTypePoints
+// On error, points before the error are returned with the error, because SVG renders them.
+// Odd count of coordinates is error, and last coordinate is dropped
+func ParsePoints(points string) ([]mgl32.Vec2, error) {
+	bts := bytes.TrimSpace([]byte(points))
+	if len(bts) == 0 {
+		return nil, nil
+	}
+	f32s, err := floats(bts)
+	if err != nil {
+		err = errors.Wrap(err, "points")
+	} else if len(f32s)%2 != 0 {
+		err = errors.New("points has odd count of coordinates")
+	}
+	res := make([]mgl32.Vec2, len(f32s)/2)
+	for i := range res {
+		res[i] = mgl32.Vec2{f32s[2*i], f32s[2*i+1]}
+	}
+	return res, err
+}
+
+// ParsePolyline return path of <polyline> with 'points' attribute,
+// path is drawn until error as ParsePoints
+func ParsePolyline(points string) ([]Elem, error) {
+	pts, err := ParsePoints(points)
+	return Polyline(pts), err
+}
+
+// ParsePolygon return path of <polygon> with 'points' attribute,
+// path is drawn until error as ParsePoints
+func ParsePolygon(points string) ([]Elem, error) {
+	pts, err := ParsePoints(points)
+	return Polygon(pts), err
+}
@@ -0,0 +1,35 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+func TestParsePoints(t *testing.T) {
+	for src, expect := range map[string][]mgl32.Vec2{
+		"":                     nil,
+		"0,0 10,0":             {{0, 0}, {10, 0}},
+		" 0 0\n\t10 , 0  5,5 ": {{0, 0}, {10, 0}, {5, 5}},
+		"0-1-2-3":              {{0, -1}, {-2, -3}},
+		"1e1,2E-1 .5.5":        {{10, .2}, {.5, .5}},
+		"-1.5e+1-2":            {{-15, -2}},
+	} {
+		res, err := ParsePoints(src)
+		if err != nil || len(res) != len(expect) {
+			t.Error(src, "must be", expect, "but", res, err)
+			continue
+		}
+		for i := range res {
+			if !res[i].ApproxEqual(expect[i]) {
+				t.Error(src, "must be", expect, "but", res)
+			}
+		}
+	}
+	// drawn until error
+	if res, err := ParsePoints("0,0 10,0 5"); err == nil || len(res) != 2 {
+		t.Error("odd count must drop last coordinate with error, but", res, err)
+	}
+	if res, err := ParsePolygon("0,0 10,0 10,10 x 5"); err == nil || len(res) != 4 {
+		t.Error("points before error must be drawn, but", res, err)
+	}
+}
@@ -0,0 +1,219 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"github.com/pkg/errors"
+	"math"
+)
+
+// Upper limit of quadratics for one cubic, same as cu2qu
+const maxQuadratics = 100
+
+// ToQuadratic approximate cubics and arcs by minimal count of CurveToQuadraticAbs,
+// each within 'tolerance'. Lines and quadratics are kept.
+// Result has only MoveToAbs, LineToAbs, CurveToQuadraticAbs and ClosePath
+func (s *Renderer) ToQuadratic(tolerance float32) []Elem {
+	res, _ := ToQuadraticCompatible(tolerance, s)
+	return res[0]
+}
+
+// ToQuadraticCompatible is ToQuadratic for several paths, for interpolation like variable font.
+// Paths must have same count of subpaths and segments, and every segment is converted
+// to same count of quadratics in all paths, which is minimal for all of them
+func ToQuadraticCompatible(tolerance float32, paths ...*Renderer) ([][]Elem, error) {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	sps := make([][]subpath, len(paths))
+	for i, path := range paths {
+		sps[i] = path.subpaths()
+		if len(sps[i]) != len(sps[0]) {
+			return nil, errors.Errorf("path %d has %d subpaths, but path 0 has %d", i, len(sps[i]), len(sps[0]))
+		}
+	}
+	res := make([][]Elem, len(paths))
+	if len(paths) == 0 {
+		return res, nil
+	}
+	for j := range sps[0] {
+		for i := range paths {
+			if len(sps[i][j].segs) != len(sps[0][j].segs) {
+				return nil, errors.Errorf("subpath %d of path %d has %d segments, but path 0 has %d", j, i, len(sps[i][j].segs), len(sps[0][j].segs))
+			}
+			res[i] = append(res[i], MoveToAbs{To: sps[i][j].start})
+		}
+		for k := range sps[0][j].segs {
+			segs := make([]segment, len(paths))
+			for i := range paths {
+				segs[i] = sps[i][j].segs[k]
+			}
+			for i, quads := range quadraticsCompatible(segs, tolerance) {
+				for _, q := range quads {
+					res[i] = append(res[i], q.elem())
+				}
+			}
+		}
+		for i := range paths {
+			if sps[i][j].closed {
+				res[i] = append(res[i], ClosePath{})
+			}
+		}
+	}
+	return res, nil
+}
+
+// quadraticsCompatible convert segments of same position in each path to same count of segments.
+// Lines are kept when all are lines, and so are quadratics
+func quadraticsCompatible(segs []segment, tolerance float32) [][]segment {
+	res := make([][]segment, len(segs))
+	same := true
+	for _, g := range segs {
+		same = same && g.kind == segs[0].kind
+	}
+	if same && (segs[0].kind == seg.LINETO_ABS || segs[0].kind == seg.CURVETO_QUADRATIC_ABS) {
+		for i, g := range segs {
+			res[i] = []segment{g}
+		}
+		return res
+	}
+	// arcs need several cubics, then others are split to same count
+	pieces := 1
+	for _, g := range segs {
+		if g.kind == seg.ARC_ABS && g.arcPieces() > pieces {
+			pieces = g.arcPieces()
+		}
+	}
+	cubics := make([][]segment, len(segs))
+	for i, g := range segs {
+		if g.kind == seg.ARC_ABS {
+			cubics[i] = g.arcCubics(pieces)
+			continue
+		}
+		for _, piece := range g.splitN(pieces) {
+			cubics[i] = append(cubics[i], piece.cubics()...)
+		}
+	}
+	for p := 0; p < pieces; p++ {
+		splines := make([][]mgl32.Vec2, len(segs))
+		for n := 1; n <= maxQuadratics; n++ {
+			ok := true
+			for i := range segs {
+				if splines[i] = quadSpline(cubics[i][p], n, tolerance, n == maxQuadratics); splines[i] == nil {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				break
+			}
+		}
+		for i, spline := range splines {
+			res[i] = append(res[i], splineQuads(spline)...)
+		}
+	}
+	return res
+}
+
+// splitN split segment to n pieces of same parameter range
+func (g segment) splitN(n int) (res []segment) {
+	for i := n; i > 1; i-- {
+		var a segment
+		a, g = g.split(1 / float32(i))
+		res = append(res, a)
+	}
+	return append(res, g)
+}
+
+// quadSpline approximate cubic by quadratic spline with n off curve points, like cu2qu.
+// Return on curve start, n control points and on curve end,
+// on curve points between controls are their midpoints.
+// Return nil if error exceed tolerance, unless 'force'
+func quadSpline(c segment, n int, tolerance float32, force bool) []mgl32.Vec2 {
+	if n == 1 {
+		q1, ok := intersectLines(c.from, c.p0, c.to, c.p1)
+		if !ok {
+			if !force {
+				return nil
+			}
+			q1 = lerp(c.p0, c.p1, .5)
+		}
+		d1 := lerp(c.from, q1, 2./3.).Sub(c.p0)
+		d2 := lerp(c.to, q1, 2./3.).Sub(c.p1)
+		if !force && !fitInside(mgl32.Vec2{}, d1, d2, mgl32.Vec2{}, tolerance) {
+			return nil
+		}
+		return []mgl32.Vec2{c.from, q1, c.to}
+	}
+	pieces := c.splitN(n)
+	control := func(t float32, p segment) mgl32.Vec2 {
+		a := lerp(p.from, p.p0, 1.5)
+		b := lerp(p.to, p.p1, 1.5)
+		return lerp(a, b, t)
+	}
+	res := []mgl32.Vec2{c.from}
+	q2, d1 := c.from, mgl32.Vec2{}
+	next := control(0, pieces[0])
+	for i := 1; i <= n; i++ {
+		p := pieces[i-1]
+		q0, q1 := q2, next
+		res = append(res, q1)
+		if i < n {
+			next = control(float32(i)/float32(n-1), pieces[i])
+			q2 = lerp(q1, next, .5)
+		} else {
+			q2 = p.to
+		}
+		d0 := d1
+		d1 = q2.Sub(p.to)
+		if force {
+			continue
+		}
+		if d1.Len() > tolerance || !fitInside(d0, lerp(q0, q1, 2./3.).Sub(p.p0), lerp(q2, q1, 2./3.).Sub(p.p1), d1, tolerance) {
+			return nil
+		}
+	}
+	return append(res, c.to)
+}
+
+// fitInside report cubic of difference between 2 curves stays within tolerance
+func fitInside(p0, p1, p2, p3 mgl32.Vec2, tolerance float32) bool {
+	if p1.Len() <= tolerance && p2.Len() <= tolerance {
+		return true
+	}
+	mid := p0.Add(p1.Add(p2).Mul(3)).Add(p3).Mul(.125)
+	if mid.Len() > tolerance {
+		return false
+	}
+	d := p3.Add(p2).Sub(p1).Sub(p0).Mul(.125)
+	return fitInside(p0, lerp(p0, p1, .5), mid.Sub(d), mid, tolerance) &&
+		fitInside(mid, mid.Add(d), lerp(p2, p3, .5), p3, tolerance)
+}
+
+// intersectLines find intersection of line a->b and line c->d
+func intersectLines(a, b, c, d mgl32.Vec2) (mgl32.Vec2, bool) {
+	ab, cd := b.Sub(a), d.Sub(c)
+	den := cross(ab, cd)
+	if den == 0 {
+		return mgl32.Vec2{}, false
+	}
+	t := cross(c.Sub(a), cd) / den
+	if math.IsInf(float64(t), 0) || math.IsNaN(float64(t)) {
+		return mgl32.Vec2{}, false
+	}
+	return a.Add(ab.Mul(t)), true
+}
+
+// splineQuads convert quadratic spline to segments with explicit on curve points
+func splineQuads(spline []mgl32.Vec2) (res []segment) {
+	from := spline[0]
+	for i := 1; i < len(spline)-1; i++ {
+		to := spline[len(spline)-1]
+		if i < len(spline)-2 {
+			to = lerp(spline[i], spline[i+1], .5)
+		}
+		res = append(res, segment{kind: seg.CURVETO_QUADRATIC_ABS, from: from, p0: spline[i], to: to})
+		from = to
+	}
+	return res
+}
@@ -0,0 +1,56 @@
+package psvg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderer_ToQuadratic(t *testing.T) {
+	circle, _ := NewRendererFromReader(strings.NewReader("M10,0 A10,10 0 0 1 -10,0 A10,10 0 0 1 10,0 Z"))
+	for _, tolerance := range []float32{1, .1, .01} {
+		res := circle.ToQuadratic(tolerance)
+		if _, ok := res[len(res)-1].(ClosePath); !ok {
+			t.Fatal("ClosePath must be kept")
+		}
+		quads := 0
+		for _, sp := range NewRenderer(res...).subpaths() {
+			for _, g := range sp.segs {
+				if _, ok := g.elem().(CurveToQuadraticAbs); !ok {
+					t.Fatal("only quadratic is expected, but", g.elem())
+				}
+				quads++
+				for i := 0; i <= 16; i++ {
+					if d := abs32(g.at(float32(i)/16).Len() - 10); d > tolerance*1.01 {
+						t.Error("error", d, "exceeds tolerance", tolerance)
+					}
+				}
+			}
+		}
+		t.Log("tolerance", tolerance, "needs", quads, "quadratics")
+	}
+	line, _ := NewRendererFromReader(strings.NewReader("M0,0 L10,0 Q10,10 0,10"))
+	if res := line.ToQuadratic(.1); len(res) != 3 {
+		t.Error("lines and quadratics must be kept, but", res)
+	}
+}
+
+func TestToQuadraticCompatible(t *testing.T) {
+	a, _ := NewRendererFromReader(strings.NewReader("M0,0 C0,10 10,10 10,0 L0,0"))
+	b, _ := NewRendererFromReader(strings.NewReader("M0,0 C0,40 30,-30 30,10 Q15,0 0,0"))
+	res, err := ToQuadraticCompatible(.05, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res[0]) != len(res[1]) {
+		t.Fatal("count of elements must be same", res)
+	}
+	for i := range res[0] {
+		if ea, eb := res[0][i], res[1][i]; ea.Type() != eb.Type() {
+			t.Error(i, "th element must be same command, but", ea, eb)
+		}
+	}
+	c, _ := NewRendererFromReader(strings.NewReader("M0,0 L10,0 L10,10 L0,10"))
+	if _, err := ToQuadraticCompatible(.05, a, c); err == nil {
+		t.Error("incompatible paths must be error")
+	}
+}
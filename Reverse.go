@@ -0,0 +1,49 @@
+package psvg
+
+import "github.com/iamGreedy/psvg/seg"
+
+// Reverse return path which draws every subpath in opposite direction.
+// Subpath order is kept, closed subpath still starts on same point and keeps ClosePath.
+// Result is made of absolute commands only, closed subpath without any segment is kept as it is.
+func (s *Renderer) Reverse() (res []Elem) {
+	for _, sp := range s.split(true) {
+		res = append(res, sp.reverse().elems()...)
+	}
+	return res
+}
+
+func (s subpath) reverse() subpath {
+	if len(s.segs) == 0 {
+		return s
+	}
+	if !s.closed {
+		res := subpath{start: s.end(), segs: make([]segment, len(s.segs))}
+		for i, g := range s.segs {
+			res.segs[len(s.segs)-1-i] = g.reverse()
+		}
+		return res
+	}
+	ring := s.ring()
+	res := subpath{start: s.start, closed: true, segs: make([]segment, 0, len(ring))}
+	for i := len(ring) - 1; i >= 0; i-- {
+		res.segs = append(res.segs, ring[i].reverse())
+	}
+	// last line is drawn by ClosePath
+	if last := res.segs[len(res.segs)-1]; last.kind == seg.LINETO_ABS && len(res.segs) > 1 {
+		res.segs = res.segs[:len(res.segs)-1]
+	}
+	return res
+}
+
+// reverse return segment drawing same curve from 'to' to 'from'
+func (g segment) reverse() segment {
+	res := g
+	res.from, res.to = g.to, g.from
+	switch g.kind {
+	case seg.CURVETO_CUBIC_ABS:
+		res.p0, res.p1 = g.p1, g.p0
+	case seg.ARC_ABS:
+		res.sweep = !g.sweep
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Reverse(t *testing.T) {
+	r, err := NewRendererFromReader(strings.NewReader("M0,0 L10,0 Q15,5 10,10 A5,5 0 0 1 0,10 M20,0 H30 V10 H20 Z M40,0 A5,5 0 0 1 50,0 A5,5 0 0 1 40,0 Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := NewRenderer(r.Reverse()...)
+	from, to := r.subpaths(), res.subpaths()
+	if len(from) != len(to) {
+		t.Fatal("reversed path must have", len(from), "subpaths, but", len(to))
+	}
+	for i := range from {
+		if from[i].closed != to[i].closed {
+			t.Error("subpath", i, "must keep closed", from[i].closed)
+		}
+		if from[i].closed {
+			if to[i].start != from[i].start {
+				t.Error("closed subpath", i, "must start on", from[i].start, "but", to[i].start)
+			}
+			continue
+		}
+		if to[i].start != from[i].end() || to[i].end() != from[i].start {
+			t.Error("open subpath", i, "must go from", from[i].end(), "to", from[i].start, "but", to[i].start, to[i].end())
+		}
+	}
+	// ClosePath is kept for each closed subpath
+	closes := 0
+	for _, e := range res.data {
+		if _, ok := e.(ClosePath); ok {
+			closes++
+		}
+	}
+	if closes != 2 {
+		t.Error("reversed path must have 2 ClosePath, but", closes)
+	}
+	if arc, ok := res.data[1].(ArcAbs); !ok || arc.Sweep || arc.To != (mgl32.Vec2{10, 10}) {
+		t.Error("arc must be reversed with sweep flag flipped, but", res.data[1])
+	}
+	twice := NewRenderer(res.Reverse()...)
+	for i, sp := range from {
+		a, b := shoelace(sp.flatten(.01)), shoelace(to[i].flatten(.01))
+		if !mgl32.FloatEqualThreshold(a, -b, 1e-3) {
+			t.Error("area of subpath", i, "must be", -a, "but", b)
+		}
+		if c := shoelace(twice.subpaths()[i].flatten(.01)); !mgl32.FloatEqualThreshold(a, c, 1e-3) {
+			t.Error("reversing twice must restore area", a, "but", c)
+		}
+	}
+
+	// empty closed subpath is kept
+	r, _ = NewRendererFromReader(strings.NewReader("M0,0 L10,0 M10,10 Z M20,20 L30,20"))
+	if d := PathData(r.Reverse()...); d != "M10 0L0 0M10 10ZM30 20L20 20" {
+		t.Error("unexpected reversed path", d)
+	}
+}
+
+// shoelace return signed area of polygon
+func shoelace(pts []mgl32.Vec2) (res float32) {
+	for i, p := range pts {
+		res += cross(p, pts[(i+1)%len(pts)])
+	}
+	return res / 2
+}
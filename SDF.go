@@ -0,0 +1,226 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"image"
+	"math"
+	"sort"
+)
+
+// Tolerance for flattening, used only for inside test of pixel centers
+const fieldTolerance = 0.05
+
+// distanceSegment is segment in pixel space with its bounding box
+type distanceSegment struct {
+	segment
+	min, max mgl32.Vec2
+}
+
+// DistanceField compute signed distance from center of each pixel to path outline,
+// positive inside and negative outside in pixels. Result is row major, width * height.
+// 'transform' maps path to pixel space, where pixel (x, y) covers [x, x+1] x [y, y+1].
+// Inside is decided by 'rule'
+func (s *Renderer) DistanceField(width, height int, transform mgl32.Mat3, rule FillRule) []float32 {
+	contours := s.distanceContours(transform)
+	inside := insideMask(fieldEdges(contours), width, height, rule)
+	var segs []distanceSegment
+	for _, contour := range contours {
+		segs = append(segs, contour...)
+	}
+	res := make([]float32, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := mgl32.Vec2{float32(x) + .5, float32(y) + .5}
+			d := float32(math.Inf(1))
+			for _, g := range segs {
+				if g.boxDistance(p) >= d {
+					continue
+				}
+				if gd, _ := g.nearest(p); gd < d {
+					d = gd
+				}
+			}
+			if !inside[y*width+x] {
+				d = -d
+			}
+			res[y*width+x] = d
+		}
+	}
+	return res
+}
+
+// SDF render DistanceField to gray image, outline is 128 (0.5).
+// 'spread' is width of distance range in pixels, mapped to [0, 255]
+func (s *Renderer) SDF(width, height int, transform mgl32.Mat3, spread float32, rule FillRule) *image.Gray {
+	res := image.NewGray(image.Rect(0, 0, width, height))
+	for i, d := range s.DistanceField(width, height, transform, rule) {
+		res.Pix[(i/width)*res.Stride+i%width] = distanceByte(d, spread)
+	}
+	return res
+}
+
+func distanceByte(d, spread float32) uint8 {
+	v := (d/spread + .5) * 255
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint8(v + .5)
+}
+
+// distanceContours transform each subpath to pixel space,
+// arcs are converted to cubic
+func (s *Renderer) distanceContours(transform mgl32.Mat3) (res [][]distanceSegment) {
+	for _, sp := range s.subpaths() {
+		var contour []distanceSegment
+		for _, g := range sp.ring() {
+			pieces := []segment{g}
+			if g.kind == seg.ARC_ABS {
+				pieces = g.cubics()
+			}
+			for _, piece := range pieces {
+				contour = append(contour, newDistanceSegment(piece.transform(transform)))
+			}
+		}
+		res = append(res, contour)
+	}
+	return res
+}
+
+func newDistanceSegment(g segment) distanceSegment {
+	res := distanceSegment{segment: g, min: g.from, max: g.from}
+	pts := []mgl32.Vec2{g.to}
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		pts = append(pts, g.p0)
+	case seg.CURVETO_CUBIC_ABS:
+		pts = append(pts, g.p0, g.p1)
+	}
+	for _, p := range pts {
+		res.min = mgl32.Vec2{min32(res.min[0], p[0]), min32(res.min[1], p[1])}
+		res.max = mgl32.Vec2{max32(res.max[0], p[0]), max32(res.max[1], p[1])}
+	}
+	return res
+}
+
+// fieldEdges flatten contours for inside test
+func fieldEdges(contours [][]distanceSegment) (res []edge) {
+	for _, contour := range contours {
+		for _, g := range contour {
+			from := g.from
+			for _, to := range g.flatten(fieldTolerance) {
+				if from != to {
+					res = append(res, edge{a: toPoint(from), b: toPoint(to)})
+				}
+				from = to
+			}
+		}
+	}
+	return res
+}
+
+// insideMask test center of every pixel by scanline
+func insideMask(edges []edge, width, height int, rule FillRule) []bool {
+	type crossing struct {
+		x float64
+		w int
+	}
+	res := make([]bool, width*height)
+	for y := 0; y < height; y++ {
+		cy := float64(y) + .5
+		var xs []crossing
+		for _, e := range edges {
+			if (e.a.y <= cy) == (e.b.y <= cy) {
+				continue
+			}
+			w := 1
+			if e.b.y < e.a.y {
+				w = -1
+			}
+			xs = append(xs, crossing{e.a.x + (cy-e.a.y)/(e.b.y-e.a.y)*(e.b.x-e.a.x), w})
+		}
+		sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+		w, i := 0, 0
+		for x := 0; x < width; x++ {
+			cx := float64(x) + .5
+			for ; i < len(xs) && xs[i].x < cx; i++ {
+				w += xs[i].w
+			}
+			res[y*width+x] = rule.filled(w)
+		}
+	}
+	return res
+}
+
+// boxDistance is lower bound of distance from p to segment
+func (s distanceSegment) boxDistance(p mgl32.Vec2) float32 {
+	dx := max32(max32(s.min[0]-p[0], p[0]-s.max[0]), 0)
+	dy := max32(max32(s.min[1]-p[1], p[1]-s.max[1]), 0)
+	return mgl32.Vec2{dx, dy}.Len()
+}
+
+// nearest return distance from p to segment and parameter of nearest point.
+// Curves are solved by newton method, started from several samples
+func (g segment) nearest(p mgl32.Vec2) (dist, t float32) {
+	if g.kind == seg.LINETO_ABS {
+		d := g.to.Sub(g.from)
+		if l := d.Dot(d); l > 0 {
+			t = mgl32.Clamp(p.Sub(g.from).Dot(d)/l, 0, 1)
+		}
+		return g.at(t).Sub(p).Len(), t
+	}
+	starts := 3
+	if g.kind == seg.CURVETO_CUBIC_ABS {
+		starts = 4
+	}
+	dist = float32(math.Inf(1))
+	for i := 0; i <= starts; i++ {
+		c := float32(i) / float32(starts)
+		for iter := 0; iter < 6; iter++ {
+			q, d1, d2 := g.at(c).Sub(p), g.derivative(c), g.secondDerivative(c)
+			den := d1.Dot(d1) + q.Dot(d2)
+			if den == 0 {
+				break
+			}
+			next := mgl32.Clamp(c-q.Dot(d1)/den, 0, 1)
+			if next == c {
+				break
+			}
+			c = next
+		}
+		if d := g.at(c).Sub(p).Len(); d < dist {
+			dist, t = d, c
+		}
+	}
+	return dist, t
+}
+
+// secondDerivative of polynomial segment, arc is not supported
+func (g segment) secondDerivative(t float32) mgl32.Vec2 {
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		return g.from.Sub(g.p0.Mul(2)).Add(g.to).Mul(2)
+	case seg.CURVETO_CUBIC_ABS:
+		a := g.from.Sub(g.p0.Mul(2)).Add(g.p1)
+		b := g.p0.Sub(g.p1.Mul(2)).Add(g.to)
+		return a.Mul(6 * (1 - t)).Add(b.Mul(6 * t))
+	}
+	return mgl32.Vec2{}
+}
+
+// transform control points of line and bezier, arc must be converted before
+func (g segment) transform(m mgl32.Mat3) segment {
+	res := g
+	res.from = transformPoint(m, g.from)
+	res.to = transformPoint(m, g.to)
+	res.p0 = transformPoint(m, g.p0)
+	res.p1 = transformPoint(m, g.p1)
+	return res
+}
+
+func transformPoint(m mgl32.Mat3, p mgl32.Vec2) mgl32.Vec2 {
+	return m.Mul3x1(p.Vec3(1)).Vec2()
+}
@@ -0,0 +1,48 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_DistanceField(t *testing.T) {
+	// circle of radius 10 at (16, 16), drawn at half scale
+	r, _ := NewRendererFromReader(strings.NewReader("M52,32 A20,20 0 0 1 12,32 A20,20 0 0 1 52,32 Z"))
+	const size = 32
+	field := r.DistanceField(size, size, mgl32.Scale2D(.5, .5), NonZero)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			p := mgl32.Vec2{float32(x) + .5, float32(y) + .5}
+			expect := 10 - p.Sub(mgl32.Vec2{16, 16}).Len()
+			if d := field[y*size+x]; math.Abs(float64(d-expect)) > 1e-2 {
+				t.Fatal("distance at", p, "must be", expect, "but", d)
+			}
+		}
+	}
+	img := r.SDF(size, size, mgl32.Scale2D(.5, .5), 8, NonZero)
+	if v := img.GrayAt(16, 16).Y; v != 255 {
+		t.Error("center must be 255, but", v)
+	}
+	if v := img.GrayAt(0, 0).Y; v != 0 {
+		t.Error("corner must be 0, but", v)
+	}
+}
+
+func TestSegment_nearest(t *testing.T) {
+	r, _ := NewRendererFromReader(strings.NewReader("M0,0 C0,30 30,-20 30,10 Q15,25 0,0"))
+	for _, g := range r.subpaths()[0].segs {
+		for _, p := range []mgl32.Vec2{{5, 5}, {15, 0}, {25, 12}, {-3, 20}, {15, 30}} {
+			d, _ := g.nearest(p)
+			// brute force
+			expect := float32(math.Inf(1))
+			for i := 0; i <= 10000; i++ {
+				expect = min32(expect, g.at(float32(i)/10000).Sub(p).Len())
+			}
+			if d > expect+1e-3 {
+				t.Error("nearest distance from", p, "must be", expect, "but", d)
+			}
+		}
+	}
+}
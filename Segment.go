@@ -0,0 +1,530 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"math"
+)
+
+// Used when caller pass tolerance <= 0
+const defaultTolerance = 0.1
+
+// Upper bound of line count when flattening one segment
+const maxFlattenSteps = 1024
+
+type (
+	// segment is one drawing command, resolved to absolute coordinate.
+	// kind is one of LINETO_ABS, CURVETO_QUADRATIC_ABS, CURVETO_CUBIC_ABS, ARC_ABS
+	segment struct {
+		kind     seg.Type
+		from, to mgl32.Vec2
+		// control points, quadratic use only p0
+		p0, p1 mgl32.Vec2
+		// only for arc
+		radius   mgl32.Vec2
+		angle    float32
+		largeArc bool
+		sweep    bool
+	}
+	// subpath is segments between MoveTo and next MoveTo
+	subpath struct {
+		start  mgl32.Vec2
+		segs   []segment
+		closed bool
+	}
+	// center parameterization of arc
+	// https://www.w3.org/TR/SVG/implnote.html#ArcConversionEndpointToCenter
+	arcParam struct {
+		cx, cy   float64
+		rx, ry   float64
+		phi      float64
+		theta    float64
+		delta    float64
+		sin, cos float64
+	}
+)
+
+// subpaths resolve relative, smooth, horizontal and vertical commands,
+// so every segment in result is one of 4 absolute kinds.
+// Subpath without any segment is dropped.
+func (s *Renderer) subpaths() []subpath {
+	return s.split(false)
+}
+
+// split path to subpaths, as subpaths does.
+// When 'dots' is true, closed subpath without any segment like "M10,10 Z" is kept
+func (s *Renderer) split(dots bool) (res []subpath) {
+	var (
+		last mgl32.Vec2
+		ctrl mgl32.Vec2
+		prev seg.Type
+		cur  *subpath
+	)
+	flush := func() {
+		if cur != nil && (len(cur.segs) > 0 || (dots && cur.closed)) {
+			res = append(res, *cur)
+		}
+		cur = nil
+	}
+	push := func(g segment) {
+		if cur == nil {
+			cur = &subpath{start: last}
+		}
+		g.from = last
+		cur.segs = append(cur.segs, g)
+		last = g.to
+		prev = g.kind
+	}
+	arc := func(to, radius mgl32.Vec2, angle float32, largeArc, sweep bool) {
+		// https://www.w3.org/TR/SVG/implnote.html#ArcOutOfRangeParameters
+		if to == last {
+			prev = seg.ARC_ABS
+			return
+		}
+		if radius[0] == 0 || radius[1] == 0 {
+			push(segment{kind: seg.LINETO_ABS, to: to})
+			return
+		}
+		push(segment{
+			kind:     seg.ARC_ABS,
+			to:       to,
+			radius:   mgl32.Vec2{abs32(radius[0]), abs32(radius[1])},
+			angle:    angle,
+			largeArc: largeArc,
+			sweep:    sweep,
+		})
+	}
+	for _, d := range s.data {
+		switch dt := d.(type) {
+		case ClosePath:
+			if cur != nil {
+				cur.closed = true
+				last = cur.start
+			}
+			flush()
+			prev = seg.CLOSEPATH
+		case MoveToAbs:
+			flush()
+			last = dt.To
+			cur = &subpath{start: last}
+			prev = seg.MOVETO_ABS
+		case MoveToRel:
+			flush()
+			last = last.Add(dt.To)
+			cur = &subpath{start: last}
+			prev = seg.MOVETO_ABS
+		case LineToAbs:
+			push(segment{kind: seg.LINETO_ABS, to: dt.To})
+		case LineToRel:
+			push(segment{kind: seg.LINETO_ABS, to: last.Add(dt.To)})
+		case LineToHorizontalAbs:
+			push(segment{kind: seg.LINETO_ABS, to: mgl32.Vec2{dt.X, last[1]}})
+		case LineToHorizontalRel:
+			push(segment{kind: seg.LINETO_ABS, to: mgl32.Vec2{last[0] + dt.X, last[1]}})
+		case LineToVerticalAbs:
+			push(segment{kind: seg.LINETO_ABS, to: mgl32.Vec2{last[0], dt.Y}})
+		case LineToVerticalRel:
+			push(segment{kind: seg.LINETO_ABS, to: mgl32.Vec2{last[0], last[1] + dt.Y}})
+		case CurveToCubicAbs:
+			push(segment{kind: seg.CURVETO_CUBIC_ABS, p0: dt.P0, p1: dt.P1, to: dt.To})
+			ctrl = dt.P1
+		case CurveToCubicRel:
+			p1 := last.Add(dt.P1)
+			push(segment{kind: seg.CURVETO_CUBIC_ABS, p0: last.Add(dt.P0), p1: p1, to: last.Add(dt.To)})
+			ctrl = p1
+		case CurveToCubicSmoothAbs:
+			p0 := last
+			if prev == seg.CURVETO_CUBIC_ABS {
+				p0 = mirrorByPoint(ctrl, last)
+			}
+			push(segment{kind: seg.CURVETO_CUBIC_ABS, p0: p0, p1: dt.P1, to: dt.To})
+			ctrl = dt.P1
+		case CurveToCubicSmoothRel:
+			p0 := last
+			if prev == seg.CURVETO_CUBIC_ABS {
+				p0 = mirrorByPoint(ctrl, last)
+			}
+			p1 := last.Add(dt.P1)
+			push(segment{kind: seg.CURVETO_CUBIC_ABS, p0: p0, p1: p1, to: last.Add(dt.To)})
+			ctrl = p1
+		case CurveToQuadraticAbs:
+			push(segment{kind: seg.CURVETO_QUADRATIC_ABS, p0: dt.P0, to: dt.To})
+			ctrl = dt.P0
+		case CurveToQuadraticRel:
+			p0 := last.Add(dt.P0)
+			push(segment{kind: seg.CURVETO_QUADRATIC_ABS, p0: p0, to: last.Add(dt.To)})
+			ctrl = p0
+		case CurveToQuadraticSmoothAbs:
+			p0 := last
+			if prev == seg.CURVETO_QUADRATIC_ABS {
+				p0 = mirrorByPoint(ctrl, last)
+			}
+			push(segment{kind: seg.CURVETO_QUADRATIC_ABS, p0: p0, to: dt.To})
+			ctrl = p0
+		case CurveToQuadraticSmoothRel:
+			p0 := last
+			if prev == seg.CURVETO_QUADRATIC_ABS {
+				p0 = mirrorByPoint(ctrl, last)
+			}
+			push(segment{kind: seg.CURVETO_QUADRATIC_ABS, p0: p0, to: last.Add(dt.To)})
+			ctrl = p0
+		case ArcAbs:
+			arc(dt.To, dt.Radius, dt.Angle, dt.LargeArc, dt.Sweep)
+		case ArcRel:
+			arc(last.Add(dt.To), dt.Radius, dt.Angle, dt.LargeArc, dt.Sweep)
+		}
+	}
+	flush()
+	return res
+}
+
+// end is current point after last segment
+func (s subpath) end() mgl32.Vec2 {
+	if len(s.segs) == 0 {
+		return s.start
+	}
+	return s.segs[len(s.segs)-1].to
+}
+
+// closing return implicit line made by ClosePath.
+// If subpath is not closed or already ends at start, it return false
+func (s subpath) closing() (segment, bool) {
+	if !s.closed || s.end() == s.start {
+		return segment{}, false
+	}
+	return segment{kind: seg.LINETO_ABS, from: s.end(), to: s.start}, true
+}
+
+// ring return every segment, include closing line, as filling does
+func (s subpath) ring() []segment {
+	res := s.segs
+	if end := s.end(); end != s.start {
+		res = append(res[:len(res):len(res)], segment{kind: seg.LINETO_ABS, from: end, to: s.start})
+	}
+	return res
+}
+
+// drawn is segments drawn by stroke, closing line is included only when closed
+func (s subpath) drawn() []segment {
+	if s.closed {
+		return s.ring()
+	}
+	return s.segs
+}
+
+// flatten subpath to polyline, first point is start point
+func (s subpath) flatten(tolerance float32) []mgl32.Vec2 {
+	res := []mgl32.Vec2{s.start}
+	for _, g := range s.segs {
+		res = append(res, g.flatten(tolerance)...)
+	}
+	return res
+}
+
+// elems convert subpath back to absolute Elem
+func (s subpath) elems() []Elem {
+	res := make([]Elem, 0, len(s.segs)+2)
+	res = append(res, MoveToAbs{To: s.start})
+	for _, g := range s.segs {
+		res = append(res, g.elem())
+	}
+	if s.closed {
+		res = append(res, ClosePath{})
+	}
+	return res
+}
+
+func (g segment) elem() Elem {
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		return CurveToQuadraticAbs{P0: g.p0, To: g.to}
+	case seg.CURVETO_CUBIC_ABS:
+		return CurveToCubicAbs{P0: g.p0, P1: g.p1, To: g.to}
+	case seg.ARC_ABS:
+		return ArcAbs{To: g.to, Radius: g.radius, Angle: g.angle, LargeArc: g.largeArc, Sweep: g.sweep}
+	}
+	return LineToAbs{To: g.to}
+}
+
+// at return point of segment on parameter t, 0 <= t <= 1
+func (g segment) at(t float32) mgl32.Vec2 {
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		return quadAt(g.from, g.p0, g.to, t)
+	case seg.CURVETO_CUBIC_ABS:
+		return cubicAt(g.from, g.p0, g.p1, g.to, t)
+	case seg.ARC_ABS:
+		return g.arcParam().at(float64(t))
+	}
+	return lerp(g.from, g.to, t)
+}
+
+// derivative return dP/dt of segment on parameter t
+func (g segment) derivative(t float32) mgl32.Vec2 {
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		return quadDerivative(g.from, g.p0, g.to, t)
+	case seg.CURVETO_CUBIC_ABS:
+		return cubicDerivative(g.from, g.p0, g.p1, g.to, t)
+	case seg.ARC_ABS:
+		return g.arcParam().derivative(float64(t))
+	}
+	return g.to.Sub(g.from)
+}
+
+// flatten approximate segment with lines, deviation is less than tolerance.
+// Result does not include 'from' point
+func (g segment) flatten(tolerance float32) []mgl32.Vec2 {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	var n int
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		dd := g.from.Sub(g.p0.Mul(2)).Add(g.to).Len()
+		n = int(math.Ceil(math.Sqrt(float64(dd / (4 * tolerance)))))
+	case seg.CURVETO_CUBIC_ABS:
+		dd := max32(
+			g.from.Sub(g.p0.Mul(2)).Add(g.p1).Len(),
+			g.p0.Sub(g.p1.Mul(2)).Add(g.to).Len(),
+		)
+		n = int(math.Ceil(math.Sqrt(float64(3 * dd / (4 * tolerance)))))
+	case seg.ARC_ABS:
+		a := g.arcParam()
+		r := math.Max(a.rx, a.ry)
+		step := math.Pi / 2
+		if float64(tolerance) < r {
+			step = 2 * math.Acos(1-float64(tolerance)/r)
+		}
+		n = int(math.Ceil(math.Abs(a.delta) / step))
+	default:
+		return []mgl32.Vec2{g.to}
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n > maxFlattenSteps {
+		n = maxFlattenSteps
+	}
+	res := make([]mgl32.Vec2, n)
+	for i := 1; i < n; i++ {
+		res[i-1] = g.at(float32(i) / float32(n))
+	}
+	res[n-1] = g.to
+	return res
+}
+
+// split segment on parameter t by de Casteljau algorithm, arc is split by angle
+func (g segment) split(t float32) (segment, segment) {
+	a, b := g, g
+	switch g.kind {
+	case seg.CURVETO_QUADRATIC_ABS:
+		p01, p12 := lerp(g.from, g.p0, t), lerp(g.p0, g.to, t)
+		mid := lerp(p01, p12, t)
+		a.p0, a.to = p01, mid
+		b.from, b.p0 = mid, p12
+	case seg.CURVETO_CUBIC_ABS:
+		p01, p12, p23 := lerp(g.from, g.p0, t), lerp(g.p0, g.p1, t), lerp(g.p1, g.to, t)
+		p012, p123 := lerp(p01, p12, t), lerp(p12, p23, t)
+		mid := lerp(p012, p123, t)
+		a.p0, a.p1, a.to = p01, p012, mid
+		b.from, b.p0, b.p1 = mid, p123, p23
+	case seg.ARC_ABS:
+		// both are arcs on same ellipse, radius is the one after scaling up
+		arc := g.arcParam()
+		mid := arc.at(float64(t))
+		a.radius = mgl32.Vec2{float32(arc.rx), float32(arc.ry)}
+		b.radius = a.radius
+		a.to, b.from = mid, mid
+		a.largeArc = math.Abs(arc.delta*float64(t)) > math.Pi
+		b.largeArc = math.Abs(arc.delta*float64(1-t)) > math.Pi
+	default:
+		mid := lerp(g.from, g.to, t)
+		a.to, b.from = mid, mid
+	}
+	return a, b
+}
+
+// cubics convert segment to cubic bezier segments.
+// Line and quadratic are exact, arc is approximated per quarter of ellipse
+func (g segment) cubics() []segment {
+	switch g.kind {
+	case seg.LINETO_ABS:
+		return []segment{{
+			kind: seg.CURVETO_CUBIC_ABS,
+			from: g.from,
+			p0:   lerp(g.from, g.to, 1./3.),
+			p1:   lerp(g.from, g.to, 2./3.),
+			to:   g.to,
+		}}
+	case seg.CURVETO_QUADRATIC_ABS:
+		return []segment{{
+			kind: seg.CURVETO_CUBIC_ABS,
+			from: g.from,
+			p0:   lerp(g.from, g.p0, 2./3.),
+			p1:   lerp(g.to, g.p0, 2./3.),
+			to:   g.to,
+		}}
+	case seg.ARC_ABS:
+		return g.arcCubics(g.arcPieces())
+	}
+	return []segment{g}
+}
+
+// arcPieces is count of cubics used for arc, one per quarter of ellipse
+func (g segment) arcPieces() int {
+	n := int(math.Ceil(math.Abs(g.arcParam().delta)/(math.Pi/2) - 1e-6))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// arcCubics approximate arc with n cubics of same angle
+func (g segment) arcCubics(n int) []segment {
+	a := g.arcParam()
+	res := make([]segment, n)
+	step := a.delta / float64(n)
+	k := 4. / 3. * math.Tan(math.Abs(step)/4)
+	from := g.from
+	for i := range res {
+		t0 := a.theta + step*float64(i)
+		t1 := t0 + step
+		to := a.point(t1)
+		if i == n-1 {
+			to = g.to
+		}
+		res[i] = segment{
+			kind: seg.CURVETO_CUBIC_ABS,
+			from: from,
+			p0:   from.Add(a.tangent(t0).Mul(float32(k))),
+			p1:   to.Sub(a.tangent(t1).Mul(float32(k))),
+			to:   to,
+		}
+		from = to
+	}
+	return res
+}
+
+func (g segment) arcParam() (res arcParam) {
+	x1, y1 := float64(g.from[0]), float64(g.from[1])
+	x2, y2 := float64(g.to[0]), float64(g.to[1])
+	res.phi = float64(g.angle) * math.Pi / 180
+	res.sin, res.cos = math.Sincos(res.phi)
+	dx, dy := (x1-x2)/2, (y1-y2)/2
+	x1p := res.cos*dx + res.sin*dy
+	y1p := -res.sin*dx + res.cos*dy
+	rx, ry := math.Abs(float64(g.radius[0])), math.Abs(float64(g.radius[1]))
+	if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+	num := rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+	den := rx*rx*y1p*y1p + ry*ry*x1p*x1p
+	var coef float64
+	if num > 0 && den > 0 {
+		coef = math.Sqrt(num / den)
+	}
+	if g.largeArc == g.sweep {
+		coef = -coef
+	}
+	cxp := coef * rx * y1p / ry
+	cyp := -coef * ry * x1p / rx
+	res.rx, res.ry = rx, ry
+	res.cx = res.cos*cxp - res.sin*cyp + (x1+x2)/2
+	res.cy = res.sin*cxp + res.cos*cyp + (y1+y2)/2
+	ux, uy := (x1p-cxp)/rx, (y1p-cyp)/ry
+	vx, vy := (-x1p-cxp)/rx, (-y1p-cyp)/ry
+	res.theta = math.Atan2(uy, ux)
+	res.delta = math.Atan2(ux*vy-uy*vx, ux*vx+uy*vy)
+	if !g.sweep && res.delta > 0 {
+		res.delta -= 2 * math.Pi
+	} else if g.sweep && res.delta < 0 {
+		res.delta += 2 * math.Pi
+	}
+	return res
+}
+
+// point on ellipse at angle theta
+func (a arcParam) point(theta float64) mgl32.Vec2 {
+	s, c := math.Sincos(theta)
+	return mgl32.Vec2{
+		float32(a.cx + a.rx*a.cos*c - a.ry*a.sin*s),
+		float32(a.cy + a.rx*a.sin*c + a.ry*a.cos*s),
+	}
+}
+
+// tangent is derivative of point by theta, scaled to arc direction
+func (a arcParam) tangent(theta float64) mgl32.Vec2 {
+	s, c := math.Sincos(theta)
+	d := mgl32.Vec2{
+		float32(-a.rx*a.cos*s - a.ry*a.sin*c),
+		float32(-a.rx*a.sin*s + a.ry*a.cos*c),
+	}
+	if a.delta < 0 {
+		return d.Mul(-1)
+	}
+	return d
+}
+
+func (a arcParam) at(t float64) mgl32.Vec2 {
+	return a.point(a.theta + a.delta*t)
+}
+
+func (a arcParam) derivative(t float64) mgl32.Vec2 {
+	return a.tangent(a.theta + a.delta*t).Mul(float32(math.Abs(a.delta)))
+}
+
+func quadAt(p0, p1, p2 mgl32.Vec2, t float32) mgl32.Vec2 {
+	mt := 1 - t
+	return p0.Mul(mt * mt).Add(p1.Mul(2 * mt * t)).Add(p2.Mul(t * t))
+}
+
+func quadDerivative(p0, p1, p2 mgl32.Vec2, t float32) mgl32.Vec2 {
+	return p1.Sub(p0).Mul(2 * (1 - t)).Add(p2.Sub(p1).Mul(2 * t))
+}
+
+func cubicAt(p0, p1, p2, p3 mgl32.Vec2, t float32) mgl32.Vec2 {
+	mt := 1 - t
+	return p0.Mul(mt * mt * mt).
+		Add(p1.Mul(3 * mt * mt * t)).
+		Add(p2.Mul(3 * mt * t * t)).
+		Add(p3.Mul(t * t * t))
+}
+
+func cubicDerivative(p0, p1, p2, p3 mgl32.Vec2, t float32) mgl32.Vec2 {
+	mt := 1 - t
+	return p1.Sub(p0).Mul(3 * mt * mt).
+		Add(p2.Sub(p1).Mul(6 * mt * t)).
+		Add(p3.Sub(p2).Mul(3 * t * t))
+}
+
+func lerp(a, b mgl32.Vec2, t float32) mgl32.Vec2 {
+	return a.Add(b.Sub(a).Mul(t))
+}
+
+// z component of 3d cross product
+func cross(a, b mgl32.Vec2) float32 {
+	return a[0]*b[1] - a[1]*b[0]
+}
+
+func abs32(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Shapes are converted to path by equivalent path of SVG 2.
+// https://www.w3.org/TR/SVG2/shapes.html
+// Radius of negative value means 'auto'.
+
+// Rect return path of <rect>, rounded when 'rx' or 'ry' is not zero.
+// Auto radius is same with the other one, and radius is clamped to half of size.
+// Zero or negative size disables rendering, so it return nil
+func Rect(x, y, width, height, rx, ry float32) []Elem {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	rx, ry = autoRadius(rx, ry)
+	rx, ry = min32(rx, width/2), min32(ry, height/2)
+	if rx == 0 || ry == 0 {
+		return []Elem{
+			MoveToAbs{To: mgl32.Vec2{x, y}},
+			LineToHorizontalAbs{X: x + width},
+			LineToVerticalAbs{Y: y + height},
+			LineToHorizontalAbs{X: x},
+			ClosePath{},
+		}
+	}
+	r := mgl32.Vec2{rx, ry}
+	return []Elem{
+		MoveToAbs{To: mgl32.Vec2{x + rx, y}},
+		LineToHorizontalAbs{X: x + width - rx},
+		ArcAbs{To: mgl32.Vec2{x + width, y + ry}, Radius: r, Sweep: true},
+		LineToVerticalAbs{Y: y + height - ry},
+		ArcAbs{To: mgl32.Vec2{x + width - rx, y + height}, Radius: r, Sweep: true},
+		LineToHorizontalAbs{X: x + rx},
+		ArcAbs{To: mgl32.Vec2{x, y + height - ry}, Radius: r, Sweep: true},
+		LineToVerticalAbs{Y: y + ry},
+		ArcAbs{To: mgl32.Vec2{x + rx, y}, Radius: r, Sweep: true},
+		ClosePath{},
+	}
+}
+
+// Circle return path of <circle>, which starts on right end and goes clockwise on screen.
+// Zero or negative radius disables rendering, so it return nil
+func Circle(cx, cy, r float32) []Elem {
+	return Ellipse(cx, cy, r, r)
+}
+
+// Ellipse return path of <ellipse>, which starts on right end and goes clockwise on screen.
+// Auto radius is same with the other one.
+// Zero radius disables rendering, so it return nil
+func Ellipse(cx, cy, rx, ry float32) []Elem {
+	rx, ry = autoRadius(rx, ry)
+	if rx <= 0 || ry <= 0 {
+		return nil
+	}
+	r := mgl32.Vec2{rx, ry}
+	return []Elem{
+		MoveToAbs{To: mgl32.Vec2{cx + rx, cy}},
+		ArcAbs{To: mgl32.Vec2{cx, cy + ry}, Radius: r, Sweep: true},
+		ArcAbs{To: mgl32.Vec2{cx - rx, cy}, Radius: r, Sweep: true},
+		ArcAbs{To: mgl32.Vec2{cx, cy - ry}, Radius: r, Sweep: true},
+		ArcAbs{To: mgl32.Vec2{cx + rx, cy}, Radius: r, Sweep: true},
+		ClosePath{},
+	}
+}
+
+// Line return path of <line>
+func Line(x1, y1, x2, y2 float32) []Elem {
+	return []Elem{
+		MoveToAbs{To: mgl32.Vec2{x1, y1}},
+		LineToAbs{To: mgl32.Vec2{x2, y2}},
+	}
+}
+
+// Polyline return path of <polyline>, it return nil for no point
+func Polyline(points []mgl32.Vec2) []Elem {
+	if len(points) == 0 {
+		return nil
+	}
+	res := []Elem{MoveToAbs{To: points[0]}}
+	for _, p := range points[1:] {
+		res = append(res, LineToAbs{To: p})
+	}
+	return res
+}
+
+// Polygon return path of <polygon>, which is closed Polyline
+func Polygon(points []mgl32.Vec2) []Elem {
+	if len(points) == 0 {
+		return nil
+	}
+	return append(Polyline(points), ClosePath{})
+}
+
+// autoRadius resolve negative radius as 'auto', both auto is zero
+func autoRadius(rx, ry float32) (float32, float32) {
+	switch {
+	case rx < 0 && ry < 0:
+		return 0, 0
+	case rx < 0:
+		return ry, ry
+	case ry < 0:
+		return rx, rx
+	}
+	return rx, ry
+}
@@ -0,0 +1,34 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"testing"
+)
+
+func TestShapes(t *testing.T) {
+	for name, c := range map[string]struct {
+		elems []Elem
+		area  float32
+	}{
+		"rect":             {Rect(1, 2, 10, 20, 0, 0), 200},
+		"rounded rect":     {Rect(1, 2, 10, 20, 2, 3), 200 - (4-math.Pi)*6},
+		"auto radius rect": {Rect(1, 2, 10, 20, -1, 3), 200 - (4-math.Pi)*9},
+		"clamped rect":     {Rect(0, 0, 10, 20, 8, -1), 200 - (4-math.Pi)*40},
+		"empty rect":       {Rect(0, 0, 0, 20, 0, 0), 0},
+		"circle":           {Circle(5, 5, 3), 9 * math.Pi},
+		"ellipse":          {Ellipse(5, 5, 3, -1), 9 * math.Pi},
+		"polygon":          {Polygon([]mgl32.Vec2{{0, 0}, {4, 0}, {4, 3}}), 6},
+		"line":             {Line(0, 0, 10, 10), 0},
+	} {
+		if a := NewRenderer(c.elems...).Area(); !mgl32.FloatEqualThreshold(a, c.area, 1e-4) {
+			t.Error(name, "must have area", c.area, "but", a)
+		}
+	}
+	if l := NewRenderer(Polyline([]mgl32.Vec2{{0, 0}, {3, 4}, {3, 0}})...).Length(); l != 9 {
+		t.Error("polyline must have length 9, but", l)
+	}
+	if Circle(0, 0, 0) != nil || Polygon(nil) != nil {
+		t.Error("empty shape must be nil")
+	}
+}
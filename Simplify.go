@@ -0,0 +1,215 @@
+package psvg
+
+import (
+	"container/heap"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"math"
+)
+
+// Curves meeting with bigger angle than this degree are not refit together
+const simplifyCorner = 2
+
+// SimplifyRDP reduce polyline by Ramer-Douglas-Peucker algorithm,
+// every removed point is within 'tolerance' from result. First and last points are kept
+func SimplifyRDP(points []mgl32.Vec2, tolerance float32) []mgl32.Vec2 {
+	if len(points) < 3 {
+		return append([]mgl32.Vec2(nil), points...)
+	}
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		far, index := float32(-1), -1
+		line := segment{kind: seg.LINETO_ABS, from: points[r[0]], to: points[r[1]]}
+		for i := r[0] + 1; i < r[1]; i++ {
+			if d, _ := line.nearest(points[i]); d > far {
+				far, index = d, i
+			}
+		}
+		if index >= 0 && far > tolerance {
+			keep[index] = true
+			stack = append(stack, [2]int{r[0], index}, [2]int{index, r[1]})
+		}
+	}
+	var res []mgl32.Vec2
+	for i, p := range points {
+		if keep[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// SimplifyVisvalingam reduce polyline by Visvalingam-Whyatt algorithm,
+// point of smallest triangle with neighbors is removed while its area is less than 'area'.
+// First and last points are kept
+func SimplifyVisvalingam(points []mgl32.Vec2, area float32) []mgl32.Vec2 {
+	n := len(points)
+	if n < 3 {
+		return append([]mgl32.Vec2(nil), points...)
+	}
+	prev, next := make([]int, n), make([]int, n)
+	areas := make([]float32, n)
+	triangle := func(i int) float32 {
+		return abs32(cross(points[i].Sub(points[prev[i]]), points[next[i]].Sub(points[i]))) / 2
+	}
+	h := &areaHeap{}
+	for i := range points {
+		prev[i], next[i] = i-1, i+1
+	}
+	for i := 1; i < n-1; i++ {
+		areas[i] = triangle(i)
+		heap.Push(h, areaItem{i, areas[i]})
+	}
+	removed := make([]bool, n)
+	// area of removed point, so neighbors never become smaller than it
+	var last float32
+	for h.Len() > 0 {
+		it := heap.Pop(h).(areaItem)
+		if removed[it.index] || it.area != areas[it.index] {
+			continue
+		}
+		if it.area >= area {
+			break
+		}
+		last = max32(last, it.area)
+		i := it.index
+		removed[i] = true
+		next[prev[i]], prev[next[i]] = next[i], prev[i]
+		for _, j := range []int{prev[i], next[i]} {
+			if j > 0 && j < n-1 {
+				areas[j] = max32(triangle(j), last)
+				heap.Push(h, areaItem{j, areas[j]})
+			}
+		}
+	}
+	var res []mgl32.Vec2
+	for i, p := range points {
+		if !removed[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+type areaItem struct {
+	index int
+	area  float32
+}
+
+type areaHeap []areaItem
+
+func (s areaHeap) Len() int            { return len(s) }
+func (s areaHeap) Less(i, j int) bool  { return s[i].area < s[j].area }
+func (s areaHeap) Swap(i, j int)       { s[i], s[j] = s[j], s[i] }
+func (s *areaHeap) Push(x interface{}) { *s = append(*s, x.(areaItem)) }
+func (s *areaHeap) Pop() interface{} {
+	old := *s
+	res := old[len(old)-1]
+	*s = old[:len(old)-1]
+	return res
+}
+
+// SimplifyLines reduce runs of LineTo by SimplifyRDP, curves are kept.
+// With zero 'tolerance', only collinear lines are merged
+func (s *Renderer) SimplifyLines(tolerance float32) []Elem {
+	return s.simplify(tolerance, false)
+}
+
+// Simplify is SimplifyLines, and also refit runs of smoothly connected curves
+// with less cubics when every point stays within 'tolerance'
+func (s *Renderer) Simplify(tolerance float32) []Elem {
+	return s.simplify(tolerance, true)
+}
+
+func (s *Renderer) simplify(tolerance float32, curves bool) (res []Elem) {
+	smooth := float32(math.Cos(float64(mgl32.DegToRad(simplifyCorner))))
+	for _, sp := range s.subpaths() {
+		res = append(res, MoveToAbs{To: sp.start})
+		for i := 0; i < len(sp.segs); {
+			// run of same kind
+			j := i + 1
+			if sp.segs[i].kind == seg.LINETO_ABS {
+				for j < len(sp.segs) && sp.segs[j].kind == seg.LINETO_ABS {
+					j++
+				}
+				pts := []mgl32.Vec2{sp.segs[i].from}
+				for _, g := range sp.segs[i:j] {
+					pts = append(pts, g.to)
+				}
+				for _, p := range SimplifyRDP(pts, tolerance)[1:] {
+					res = append(res, LineToAbs{To: p})
+				}
+				i = j
+				continue
+			}
+			for j < len(sp.segs) && sp.segs[j].kind != seg.LINETO_ABS {
+				a, b := sp.segs[j-1].direction(1).Normalize(), sp.segs[j].direction(0).Normalize()
+				if a.Dot(b) < smooth {
+					break
+				}
+				j++
+			}
+			run := sp.segs[i:j]
+			if curves {
+				if fit := refit(run, tolerance); len(fit) < len(run) {
+					run = fit
+				}
+			}
+			for _, g := range run {
+				res = append(res, g.elem())
+			}
+			i = j
+		}
+		if sp.closed {
+			res = append(res, ClosePath{})
+		}
+	}
+	return res
+}
+
+// refit merge adjacent curves greedily, while one cubic fits points sampled from them
+func refit(run []segment, tolerance float32) (res []segment) {
+	if tolerance <= 0 {
+		return run
+	}
+	for i := 0; i < len(run); {
+		end, fit := i+1, run[i]
+		for j := i + 2; j <= len(run); j++ {
+			pts := samplePoints(run[i:j], tolerance)
+			if len(pts) < 2 {
+				break
+			}
+			left := run[i].direction(0).Normalize()
+			right := run[j-1].direction(1).Normalize().Mul(-1)
+			g, _, ok := fitSingle(pts, left, right, tolerance*tolerance)
+			if !ok {
+				break
+			}
+			end, fit = j, g
+		}
+		res = append(res, fit)
+		i = end
+	}
+	return res
+}
+
+// samplePoints sample curves densely enough for fitting
+func samplePoints(run []segment, tolerance float32) []mgl32.Vec2 {
+	pts := []mgl32.Vec2{run[0].from}
+	for _, g := range run {
+		n := len(g.flatten(tolerance / 4))
+		if n < 4 {
+			n = 4
+		}
+		for k := 1; k <= n; k++ {
+			if p := g.at(float32(k) / float32(n)); p != pts[len(pts)-1] {
+				pts = append(pts, p)
+			}
+		}
+	}
+	return pts
+}
@@ -0,0 +1,49 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSimplifyRDP(t *testing.T) {
+	pts := []mgl32.Vec2{{0, 0}, {1, 0.05}, {2, -0.05}, {3, 0}, {3, 1}, {3, 2}, {4, 2}}
+	expect := []mgl32.Vec2{{0, 0}, {3, 0}, {3, 2}, {4, 2}}
+	if res := SimplifyRDP(pts, .1); len(res) != len(expect) {
+		t.Error("rdp must be", expect, "but", res)
+	}
+	if res := SimplifyVisvalingam(pts, .2); len(res) != len(expect) {
+		t.Error("visvalingam must be", expect, "but", res)
+	}
+}
+
+func TestRenderer_Simplify(t *testing.T) {
+	r, _ := NewRendererFromReader(strings.NewReader("M0,0 L1,0 L2,0 L3,0 L3,3 L0,3 Z"))
+	if res := r.SimplifyLines(0); len(res) != 5 {
+		t.Error("collinear lines must be merged, but", res)
+	}
+	// circle split to many arcs
+	src := "M10,0"
+	for i := 1; i <= 36; i++ {
+		s, c := math.Sincos(float64(i) * math.Pi / 18)
+		src += " A10,10 0 0 1 " + ftoa(10*c) + "," + ftoa(10*s)
+	}
+	r, _ = NewRendererFromReader(strings.NewReader(src + " Z"))
+	res := r.Simplify(.01)
+	if len(res) > 8 {
+		t.Error("arcs must be refit with few curves, but", len(res))
+	}
+	for _, g := range NewRenderer(res...).subpaths()[0].segs {
+		for i := 0; i <= 16; i++ {
+			if d := abs32(g.at(float32(i)/16).Len() - 10); d > .011 {
+				t.Error("error", d, "exceeds tolerance")
+			}
+		}
+	}
+}
+
+func ftoa(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,159 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"math"
+)
+
+// Pieces of quadrature for length of curve
+const lengthPieces = 16
+
+// SplitSegment split drawing element 'e' starting at 'from' on parameter t, 0 <= t <= 1.
+// Bezier is split by de Casteljau algorithm and arc is split to 2 arcs.
+// Result is absolute, relative, smooth, horizontal and vertical elements are resolved.
+// If 'e' does not draw, it return nil
+func SplitSegment(from mgl32.Vec2, e Elem, t float32) (Elem, Elem) {
+	sps := NewRenderer(MoveToAbs{To: from}, e).subpaths()
+	if len(sps) == 0 || len(sps[0].segs) != 1 {
+		return nil, nil
+	}
+	a, b := sps[0].segs[0].split(mgl32.Clamp(t, 0, 1))
+	return a.elem(), b.elem()
+}
+
+// Length return total length of path, closing line of closed subpath is included
+func (s *Renderer) Length() (res float32) {
+	for _, sp := range s.subpaths() {
+		for _, g := range sp.drawn() {
+			res += g.length(1)
+		}
+	}
+	return res
+}
+
+// SplitAt cut path on parameter t of i th segment, and return both sides.
+// Segments are counted through all subpaths, closing line of closed subpath included.
+// Subpath which is cut becomes open, cutting on end of segment makes no zero length segment
+func (s *Renderer) SplitAt(i int, t float32) ([]Elem, []Elem) {
+	return s.splitAt(func(index int, g segment) (float32, bool) {
+		return mgl32.Clamp(t, 0, 1), index == i
+	})
+}
+
+// SplitAtLength cut path at 'length' along it, and return both sides.
+// Length is measured same as Length, length on end of segment cuts between segments
+func (s *Renderer) SplitAtLength(length float32) ([]Elem, []Elem) {
+	done := float32(0)
+	return s.splitAt(func(index int, g segment) (float32, bool) {
+		l := g.length(1)
+		if done+l < length {
+			done += l
+			return 0, false
+		}
+		return g.paramAt(length - done), true
+	})
+}
+
+// splitAt cut path on first segment, which 'cut' return true
+func (s *Renderer) splitAt(cut func(index int, g segment) (float32, bool)) (first, second []Elem) {
+	index := 0
+	found := false
+	for _, sp := range s.subpaths() {
+		if found {
+			second = append(second, sp.elems()...)
+			continue
+		}
+		segs := sp.drawn()
+		for k, g := range segs {
+			t, ok := cut(index, g)
+			index++
+			if !ok {
+				continue
+			}
+			found = true
+			before, after := segs[:k], segs[k+1:]
+			switch {
+			// cut on end point is between segments, without zero length one
+			case t <= 0:
+				after = segs[k:]
+			case t >= 1:
+				before = segs[:k+1]
+			default:
+				a, b := g.split(t)
+				before = append(before[:k:k], a)
+				after = append([]segment{b}, after...)
+			}
+			if len(before) > 0 {
+				first = append(first, MoveToAbs{To: sp.start})
+				for _, g := range before {
+					first = append(first, g.elem())
+				}
+			}
+			if len(after) > 0 {
+				second = append(second, MoveToAbs{To: after[0].from})
+				for _, g := range after {
+					second = append(second, g.elem())
+				}
+			}
+			break
+		}
+		if !found {
+			first = append(first, sp.elems()...)
+		}
+	}
+	return first, second
+}
+
+// length of segment from parameter 0 to t
+func (g segment) length(t float32) float32 {
+	if g.kind == seg.LINETO_ABS {
+		return g.to.Sub(g.from).Len() * t
+	}
+	speed := func(t float64) float64 { return float64(g.derivative(float32(t)).Len()) }
+	if g.kind == seg.ARC_ABS {
+		arc := g.arcParam()
+		speed = func(t float64) float64 { return float64(arc.derivative(t).Len()) }
+	}
+	var res float64
+	step := float64(t) / lengthPieces
+	for i := 0; i < lengthPieces; i++ {
+		for k, node := range gaussNodes {
+			res += speed(step*(float64(i)+node)) * gaussWeights[k] * step
+		}
+	}
+	return float32(res)
+}
+
+// paramAt find parameter where length from start is 'length', by newton method kept in bracket
+func (g segment) paramAt(length float32) float32 {
+	total := g.length(1)
+	if length <= 0 || total == 0 {
+		return 0
+	}
+	if length >= total {
+		return 1
+	}
+	lo, hi := float32(0), float32(1)
+	t := length / total
+	for i := 0; i < 16; i++ {
+		f := g.length(t) - length
+		if math.Abs(float64(f)) < 1e-5*float64(total) {
+			break
+		}
+		if f > 0 {
+			hi = t
+		} else {
+			lo = t
+		}
+		next := t
+		if d := g.derivative(t).Len(); d > 0 {
+			next = t - f/d
+		}
+		if next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		t = next
+	}
+	return t
+}
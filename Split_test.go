@@ -0,0 +1,67 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSplitSegment(t *testing.T) {
+	a, b := SplitSegment(mgl32.Vec2{10, 0}, ArcAbs{To: mgl32.Vec2{0, 10}, Radius: mgl32.Vec2{10, 10}, Sweep: true}, .5)
+	mid := mgl32.Vec2{float32(10 * math.Sqrt2 / 2), float32(10 * math.Sqrt2 / 2)}
+	if arc, ok := a.(ArcAbs); !ok || !arc.To.ApproxEqualThreshold(mid, 1e-4) || arc.LargeArc || !arc.Sweep {
+		t.Error("first half must be arc to", mid, "but", a)
+	}
+	if arc, ok := b.(ArcAbs); !ok || arc.To != (mgl32.Vec2{0, 10}) {
+		t.Error("second half must be arc to (0, 10), but", b)
+	}
+	a, b = SplitSegment(mgl32.Vec2{0, 0}, CurveToCubicRel{P0: mgl32.Vec2{0, 10}, P1: mgl32.Vec2{10, 10}, To: mgl32.Vec2{10, 0}}, .5)
+	if c, ok := a.(CurveToCubicAbs); !ok || c.To != (mgl32.Vec2{5, 7.5}) || c.P0 != (mgl32.Vec2{0, 5}) {
+		t.Error("cubic is split wrong", a)
+	}
+	if c, ok := b.(CurveToCubicAbs); !ok || c.P1 != (mgl32.Vec2{10, 5}) {
+		t.Error("cubic is split wrong", b)
+	}
+	if a, b := SplitSegment(mgl32.Vec2{}, ClosePath{}, .5); a != nil || b != nil {
+		t.Error("ClosePath can not be split")
+	}
+}
+
+func TestRenderer_SplitAtLength(t *testing.T) {
+	circle, _ := NewRendererFromReader(strings.NewReader("M10,0 A10,10 0 0 1 -10,0 A10,10 0 0 1 10,0 Z"))
+	if l := circle.Length(); !mgl32.FloatEqualThreshold(l, 20*math.Pi, 1e-4) {
+		t.Error("length of circle must be", 20*math.Pi, "but", l)
+	}
+	a, b := circle.SplitAtLength(5 * math.Pi)
+	if l := NewRenderer(a...).Length(); !mgl32.FloatEqualThreshold(l, 5*math.Pi, 1e-3) {
+		t.Error("first part must have length", 5*math.Pi, "but", l)
+	}
+	if l := NewRenderer(b...).Length(); !mgl32.FloatEqualThreshold(l, 15*math.Pi, 1e-3) {
+		t.Error("second part must have length", 15*math.Pi, "but", l)
+	}
+	if to := a[len(a)-1].(ArcAbs).To; !to.ApproxEqualThreshold(mgl32.Vec2{0, 10}, 1e-3) {
+		t.Error("cut point must be (0, 10), but", to)
+	}
+	square, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z M20,0 H30"))
+	a, b = square.SplitAt(3, .5)
+	if len(a) != 5 || a[4].(LineToAbs).To != (mgl32.Vec2{0, 5}) {
+		t.Error("closing line must be cut", a)
+	}
+	if len(b) != 4 || b[1].(LineToAbs).To != (mgl32.Vec2{0, 0}) {
+		t.Error("rest must be closing line and next subpath", b)
+	}
+
+	// length on end of segment cuts between segments
+	for l, expect := range map[float32][2]string{
+		0:  {"", "M0 0L10 0L10 10L0 10L0 0M20 0L30 0"},
+		10: {"M0 0L10 0", "M10 0L10 10L0 10L0 0M20 0L30 0"},
+		40: {"M0 0L10 0L10 10L0 10L0 0", "M20 0L30 0"},
+		50: {"M0 0L10 0L10 10L0 10ZM20 0L30 0", ""},
+	} {
+		a, b := square.SplitAtLength(l)
+		if PathData(a...) != expect[0] || PathData(b...) != expect[1] {
+			t.Error("cut on", l, "must be", expect, "but", PathData(a...), PathData(b...))
+		}
+	}
+}
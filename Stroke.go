@@ -0,0 +1,271 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+)
+
+// https://www.w3.org/TR/SVG/painting.html#StrokeLinejoinProperty
+type LineJoin uint8
+
+// https://www.w3.org/TR/SVG/painting.html#StrokeLinecapProperty
+type LineCap uint8
+
+const (
+	MiterJoin LineJoin = iota
+	RoundJoin
+	BevelJoin
+)
+
+const (
+	ButtCap LineCap = iota
+	RoundCap
+	SquareCap
+)
+
+// SVG initial value of stroke-miterlimit
+const defaultMiterLimit = 4
+
+type StrokeStyle struct {
+	Width float32
+	Join  LineJoin
+	Cap   LineCap
+	// Zero means SVG default, 4
+	MiterLimit float32
+}
+
+type strokeBuilder struct {
+	mesh     *Mesh
+	style    StrokeStyle
+	half     float32
+	step     float64
+	distance bool
+}
+
+func (s LineJoin) String() string {
+	switch s {
+	case RoundJoin:
+		return "round"
+	case BevelJoin:
+		return "bevel"
+	}
+	return "miter"
+}
+
+func (s LineCap) String() string {
+	switch s {
+	case RoundCap:
+		return "round"
+	case SquareCap:
+		return "square"
+	}
+	return "butt"
+}
+
+// Stroke tessellate outline of path stroked by 'style' to indexed triangle list, not strips,
+// so whole path is drawn with one draw call.
+// Zero length subpath like "M5,5 Z" is drawn as circle or square by round and square cap.
+// Triangles of segments and joins may overlap each other on inner side of corner,
+// and their winding is not consistent, so face culling must be disabled.
+// If 'distance' is true, Mesh.Distances is filled with length along subpath of each vertex,
+// which can be used for dashing in shader.
+// Curves are flattened with 'tolerance'
+func (s *Renderer) Stroke(style StrokeStyle, tolerance float32, distance bool) *Mesh {
+	if tolerance <= 0 {
+		tolerance = defaultTolerance
+	}
+	if style.MiterLimit < 1 {
+		style.MiterLimit = defaultMiterLimit
+	}
+	b := &strokeBuilder{
+		mesh:     &Mesh{},
+		style:    style,
+		half:     style.Width / 2,
+		step:     math.Pi / 2,
+		distance: distance,
+	}
+	if b.half <= 0 {
+		return b.mesh
+	}
+	if tolerance < b.half {
+		b.step = 2 * math.Acos(1-float64(tolerance/b.half))
+	}
+	for _, sp := range s.split(true) {
+		pts, corners := strokePolyline(sp, tolerance)
+		b.polyline(pts, corners, sp.closed)
+	}
+	return b.mesh
+}
+
+// strokePolyline flatten subpath and mark points where segments meet
+func strokePolyline(sp subpath, tolerance float32) (pts []mgl32.Vec2, corners []bool) {
+	pts, corners = []mgl32.Vec2{sp.start}, []bool{true}
+	segs := sp.segs
+	if sp.closed {
+		segs = sp.ring()
+	}
+	for _, g := range segs {
+		flat := g.flatten(tolerance)
+		for i, p := range flat {
+			if p == pts[len(pts)-1] {
+				continue
+			}
+			pts = append(pts, p)
+			corners = append(corners, i == len(flat)-1)
+		}
+	}
+	if sp.closed && len(pts) > 1 && pts[len(pts)-1] == pts[0] {
+		pts, corners = pts[:len(pts)-1], corners[:len(corners)-1]
+	}
+	return pts, corners
+}
+
+func (s *strokeBuilder) polyline(pts []mgl32.Vec2, corners []bool, closed bool) {
+	if len(pts) < 2 {
+		// zero length subpath has caps in direction of x axis
+		x := mgl32.Vec2{1, 0}
+		s.cap(pts[0].Sub(x), pts[0], 0)
+		s.cap(pts[0].Add(x), pts[0], 0)
+		return
+	}
+	n := len(pts)
+	edges := n - 1
+	if closed {
+		edges = n
+	}
+	dist := make([]float32, edges+1)
+	for i := 0; i < edges; i++ {
+		dist[i+1] = dist[i] + pts[(i+1)%n].Sub(pts[i]).Len()
+	}
+	for i := 0; i < edges; i++ {
+		a, b := pts[i], pts[(i+1)%n]
+		nm := s.normal(a, b)
+		va := s.vertex(a.Add(nm), dist[i])
+		vb := s.vertex(a.Sub(nm), dist[i])
+		vc := s.vertex(b.Add(nm), dist[i+1])
+		vd := s.vertex(b.Sub(nm), dist[i+1])
+		s.triangle(va, vb, vc)
+		s.triangle(vc, vb, vd)
+	}
+	// joins
+	for i := 0; i < n; i++ {
+		if !closed && (i == 0 || i == n-1) {
+			continue
+		}
+		prev, cur, next := pts[(i+n-1)%n], pts[i], pts[(i+1)%n]
+		d := dist[i]
+		if closed && i == 0 {
+			d = dist[edges]
+		}
+		join := s.style.Join
+		if !corners[i] {
+			join = BevelJoin
+		}
+		s.join(prev, cur, next, join, d)
+	}
+	if !closed {
+		s.cap(pts[1], pts[0], 0)
+		s.cap(pts[n-2], pts[n-1], dist[edges])
+	}
+}
+
+// join fill gap on outer side of corner at 'cur'
+func (s *strokeBuilder) join(prev, cur, next mgl32.Vec2, join LineJoin, d float32) {
+	d0, d1 := cur.Sub(prev), next.Sub(cur)
+	turn := cross(d0, d1)
+	if turn == 0 && d0.Dot(d1) > 0 {
+		return
+	}
+	n0, n1 := s.normal(prev, cur), s.normal(cur, next)
+	// outer side is right of direction when turning left
+	if turn > 0 {
+		n0, n1 = n0.Mul(-1), n1.Mul(-1)
+	}
+	center := s.vertex(cur, d)
+	switch join {
+	case RoundJoin:
+		s.fan(cur, n0, n1, d0, center, d)
+		return
+	case MiterJoin:
+		// ratio of miter length and stroke width is 1/sin(theta/2)
+		bisector := n0.Add(n1)
+		if l := bisector.Len(); l > 0 {
+			cosHalf := bisector.Dot(n0) / (l * s.half)
+			if cosHalf > 0 && 1/cosHalf <= s.style.MiterLimit {
+				tip := s.vertex(cur.Add(bisector.Mul(s.half/(l*cosHalf))), d)
+				a, b := s.vertex(cur.Add(n0), d), s.vertex(cur.Add(n1), d)
+				s.triangle(center, a, tip)
+				s.triangle(center, tip, b)
+				return
+			}
+		}
+	}
+	s.triangle(center, s.vertex(cur.Add(n0), d), s.vertex(cur.Add(n1), d))
+}
+
+// cap draw end of open subpath at 'end', 'from' is previous point
+func (s *strokeBuilder) cap(from, end mgl32.Vec2, d float32) {
+	nm := s.normal(from, end)
+	switch s.style.Cap {
+	case SquareCap:
+		ext := end.Sub(from).Normalize().Mul(s.half)
+		va, vb := s.vertex(end.Add(nm), d), s.vertex(end.Sub(nm), d)
+		vc, vd := s.vertex(end.Add(nm).Add(ext), d), s.vertex(end.Sub(nm).Add(ext), d)
+		s.triangle(va, vb, vc)
+		s.triangle(vc, vb, vd)
+	case RoundCap:
+		s.fan(end, nm, nm.Mul(-1), end.Sub(from), s.vertex(end, d), d)
+	}
+}
+
+// fan draw circular sector around 'center' from offset 'a' to 'b' in shorter way.
+// When they are opposite, half circle goes through 'forward' side
+func (s *strokeBuilder) fan(center, a, b, forward mgl32.Vec2, vc uint32, d float32) {
+	from := math.Atan2(float64(a[1]), float64(a[0]))
+	sweep := math.Atan2(float64(cross(a, b)), float64(a.Dot(b)))
+	if cross(a, b) == 0 && a.Dot(b) < 0 {
+		sweep = math.Pi
+		if forward.Dot(mgl32.Vec2{a[1], -a[0]}) > 0 {
+			sweep = -math.Pi
+		}
+	}
+	steps := int(math.Ceil(math.Abs(sweep) / s.step))
+	if steps < 1 {
+		steps = 1
+	}
+	prev := s.vertex(center.Add(a), d)
+	for i := 1; i <= steps; i++ {
+		var p mgl32.Vec2
+		if i == steps {
+			p = center.Add(b)
+		} else {
+			sin, cos := math.Sincos(from + sweep*float64(i)/float64(steps))
+			p = center.Add(mgl32.Vec2{float32(cos), float32(sin)}.Mul(s.half))
+		}
+		cur := s.vertex(p, d)
+		s.triangle(vc, prev, cur)
+		prev = cur
+	}
+}
+
+// normal is left side normal of a->b, with half of stroke width
+func (s *strokeBuilder) normal(a, b mgl32.Vec2) mgl32.Vec2 {
+	d := b.Sub(a)
+	l := d.Len()
+	if l == 0 {
+		return mgl32.Vec2{}
+	}
+	return mgl32.Vec2{-d[1], d[0]}.Mul(s.half / l)
+}
+
+func (s *strokeBuilder) vertex(p mgl32.Vec2, d float32) uint32 {
+	s.mesh.Vertices = append(s.mesh.Vertices, p)
+	if s.distance {
+		s.mesh.Distances = append(s.mesh.Distances, d)
+	}
+	return uint32(len(s.mesh.Vertices) - 1)
+}
+
+func (s *strokeBuilder) triangle(a, b, c uint32) {
+	s.mesh.Indices = append(s.mesh.Indices, a, b, c)
+}
@@ -0,0 +1,172 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"math"
+	"strings"
+	"testing"
+)
+
+func strokeMesh(t *testing.T, d string, style StrokeStyle) *Mesh {
+	r, err := NewRendererFromReader(strings.NewReader(d))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Stroke(style, 0, true)
+	if len(m.Indices)%3 != 0 {
+		t.Fatal("indices must be triangles, but", len(m.Indices))
+	}
+	for _, i := range m.Indices {
+		if int(i) >= len(m.Vertices) {
+			t.Fatal("index", i, "is out of", len(m.Vertices), "vertices")
+		}
+	}
+	if len(m.Distances) != len(m.Vertices) {
+		t.Fatal("distances must be same length with vertices", len(m.Vertices), "but", len(m.Distances))
+	}
+	return m
+}
+
+func hasVertex(m *Mesh, p mgl32.Vec2) bool {
+	for _, v := range m.Vertices {
+		if v.Sub(p).Len() < 1e-4 {
+			return true
+		}
+	}
+	return false
+}
+
+// farthest return max distance from p of vertices near p, in 'radius'
+func farthest(m *Mesh, p mgl32.Vec2, radius float32) (res float32) {
+	for _, v := range m.Vertices {
+		if d := v.Sub(p).Len(); d < radius {
+			res = max32(res, d)
+		}
+	}
+	return res
+}
+
+// right return max x of vertices
+func right(m *Mesh) (res float32) {
+	res = m.Vertices[0][0]
+	for _, v := range m.Vertices {
+		res = max32(res, v[0])
+	}
+	return res
+}
+
+func TestRenderer_StrokeJoin(t *testing.T) {
+	corner := "M0,0 L10,0 L10,10"
+	miter := strokeMesh(t, corner, StrokeStyle{Width: 2, Join: MiterJoin})
+	if !hasVertex(miter, mgl32.Vec2{11, -1}) {
+		t.Error("miter join must have tip on (11, -1)")
+	}
+	bevel := strokeMesh(t, corner, StrokeStyle{Width: 2, Join: BevelJoin})
+	if hasVertex(bevel, mgl32.Vec2{11, -1}) || !hasVertex(bevel, mgl32.Vec2{10, -1}) || !hasVertex(bevel, mgl32.Vec2{11, 0}) {
+		t.Error("bevel join must cut corner between (10, -1) and (11, 0)")
+	}
+	round := strokeMesh(t, corner, StrokeStyle{Width: 2, Join: RoundJoin})
+	if d := farthest(round, mgl32.Vec2{10, 0}, 2); d > 1+1e-4 {
+		t.Error("round join must be in radius 1 from corner, but", d)
+	}
+	if len(round.Vertices) <= len(bevel.Vertices) {
+		t.Error("round join must have more vertices than bevel join")
+	}
+
+	// miter of sharp corner is longer than limit, so it becomes bevel
+	sharp := "M0,0 L10,0 L0,1"
+	limited := strokeMesh(t, sharp, StrokeStyle{Width: 2, Join: MiterJoin, MiterLimit: 4})
+	if x := right(limited); x > 11 {
+		t.Error("miter over limit must be bevel, but it reaches", x)
+	}
+	unlimited := strokeMesh(t, sharp, StrokeStyle{Width: 2, Join: MiterJoin, MiterLimit: 100})
+	if x := right(unlimited); x < 14 {
+		t.Error("miter under limit must reach far, but", x)
+	}
+}
+
+func TestRenderer_StrokeCap(t *testing.T) {
+	line := "M0,0 L10,0"
+	bounds := func(m *Mesh) (lo, hi float32) {
+		lo, hi = m.Vertices[0][0], m.Vertices[0][0]
+		for _, v := range m.Vertices {
+			lo, hi = min32(lo, v[0]), max32(hi, v[0])
+		}
+		return lo, hi
+	}
+	for c, expect := range map[LineCap][2]float32{
+		ButtCap:   {0, 10},
+		RoundCap:  {-1, 11},
+		SquareCap: {-1, 11},
+	} {
+		m := strokeMesh(t, line, StrokeStyle{Width: 2, Cap: c})
+		if lo, hi := bounds(m); !mgl32.FloatEqualThreshold(lo, expect[0], 1e-4) || !mgl32.FloatEqualThreshold(hi, expect[1], 1e-4) {
+			t.Error(c, "cap must cover", expect, "but", lo, hi)
+		}
+	}
+	if !hasVertex(strokeMesh(t, line, StrokeStyle{Width: 2, Cap: SquareCap}), mgl32.Vec2{11, 1}) {
+		t.Error("square cap must have corner on (11, 1)")
+	}
+	if hasVertex(strokeMesh(t, line, StrokeStyle{Width: 2, Cap: RoundCap}), mgl32.Vec2{11, 1}) {
+		t.Error("round cap must not have corner on (11, 1)")
+	}
+
+	// closed subpath has no cap
+	square := "M0,0 H10 V10 H0 Z"
+	butt := strokeMesh(t, square, StrokeStyle{Width: 2, Cap: ButtCap})
+	for _, c := range []LineCap{RoundCap, SquareCap} {
+		if m := strokeMesh(t, square, StrokeStyle{Width: 2, Cap: c}); len(m.Vertices) != len(butt.Vertices) || len(m.Indices) != len(butt.Indices) {
+			t.Error("closed subpath must not have", c, "cap")
+		}
+	}
+}
+
+func TestRenderer_StrokeDot(t *testing.T) {
+	// winding of stroke triangles is not consistent
+	area := func(m *Mesh) (res float32) {
+		for i := 0; i < len(m.Indices); i += 3 {
+			a, b, c := m.Vertices[m.Indices[i]], m.Vertices[m.Indices[i+1]], m.Vertices[m.Indices[i+2]]
+			res += abs32(cross(b.Sub(a), c.Sub(a))) / 2
+		}
+		return res
+	}
+	for _, d := range []string{"M5,5 Z", "M5,5 L5,5", "M5,5 L5,5 Z"} {
+		if m := strokeMesh(t, d, StrokeStyle{Width: 2, Cap: ButtCap}); len(m.Indices) != 0 {
+			t.Error(d, "must not be drawn with butt cap")
+		}
+		round := strokeMesh(t, d, StrokeStyle{Width: 2, Cap: RoundCap})
+		if a := area(round); a < 2.5 || a > math.Pi {
+			t.Error(d, "must be circle with round cap, but area", a)
+		}
+		if r := farthest(round, mgl32.Vec2{5, 5}, 2); !mgl32.FloatEqualThreshold(r, 1, 1e-4) {
+			t.Error(d, "must be circle in radius 1, but", r)
+		}
+		square := strokeMesh(t, d, StrokeStyle{Width: 2, Cap: SquareCap})
+		if a := area(square); !mgl32.FloatEqualThreshold(a, 4, 1e-4) {
+			t.Error(d, "must be square with square cap, but area", a)
+		}
+		for _, p := range []mgl32.Vec2{{4, 4}, {6, 4}, {6, 6}, {4, 6}} {
+			if !hasVertex(square, p) {
+				t.Error(d, "must have corner on", p)
+			}
+		}
+	}
+}
+
+func TestRenderer_StrokeDistance(t *testing.T) {
+	r, _ := NewRendererFromReader(strings.NewReader("M0,0 L10,0 L10,10"))
+	if m := r.Stroke(StrokeStyle{Width: 2}, 0, false); m.Distances != nil {
+		t.Error("distances must be empty without 'distance'")
+	}
+	m := strokeMesh(t, "M0,0 L10,0 L10,10", StrokeStyle{Width: 2, Cap: RoundCap})
+	var most float32
+	for _, d := range m.Distances {
+		most = max32(most, d)
+	}
+	if most != 20 {
+		t.Error("last distance must be length 20, but", most)
+	}
+	if r.Stroke(StrokeStyle{}, 0, true).Vertices != nil {
+		t.Error("zero width must have no vertex")
+	}
+}
@@ -0,0 +1,315 @@
+package psvg
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Paint is value of 'fill' and 'stroke'.
+// https://www.w3.org/TR/SVG11/painting.html#SpecifyingPaint
+type Paint struct {
+	// None paints nothing, also when it is fallback of URL
+	None bool
+	// Color is used when URL is empty, or as fallback of URL
+	Color color.NRGBA
+	// URL is id of referenced paint server like gradient, without '#'
+	URL string
+	// currentColor, resolved after cascade
+	current bool
+}
+
+// Style is computed value of presentation properties of a shape.
+// It is cascaded from presentation attributes and 'style' attribute,
+// and inherited through groups and <use>. <style> sheets are not supported
+type Style struct {
+	Fill        Paint
+	FillRule    FillRule
+	FillOpacity float32
+	Stroke      Paint
+	// Width of zero means no stroke
+	StrokeStyle   StrokeStyle
+	StrokeOpacity float32
+	// Opacity is multiplied through ancestors, so group opacity is applied to each shape
+	Opacity float32
+	// Value of 'color', used by currentColor
+	Color color.NRGBA
+	// 'visibility' is not hidden nor collapse
+	Visible bool
+}
+
+var black = color.NRGBA{A: 255}
+
+// initialStyle is style of root, initial values of SVG
+func initialStyle() Style {
+	return Style{
+		Fill:          Paint{Color: black},
+		FillOpacity:   1,
+		Stroke:        Paint{None: true},
+		StrokeStyle:   StrokeStyle{Width: 1, MiterLimit: defaultMiterLimit},
+		StrokeOpacity: 1,
+		Opacity:       1,
+		Color:         black,
+		Visible:       true,
+	}
+}
+
+// style compute style of last element of 'tree' from its ancestors
+func style(tree []*node) Style {
+	res := initialStyle()
+	for _, n := range tree {
+		res = res.apply(n.declarations())
+	}
+	if res.Fill.current {
+		res.Fill.Color = res.Color
+	}
+	if res.Stroke.current {
+		res.Stroke.Color = res.Color
+	}
+	return res
+}
+
+// apply declarations of element to style inherited from parent.
+// Invalid and 'inherit' value keep inherited one
+func (s Style) apply(decls map[string]string) Style {
+	for name, v := range decls {
+		switch name {
+		case "fill":
+			if p, ok := parsePaint(v); ok {
+				s.Fill = p
+			}
+		case "stroke":
+			if p, ok := parsePaint(v); ok {
+				s.Stroke = p
+			}
+		case "fill-rule":
+			switch v {
+			case "nonzero":
+				s.FillRule = NonZero
+			case "evenodd":
+				s.FillRule = EvenOdd
+			}
+		case "stroke-linejoin":
+			switch v {
+			case "miter":
+				s.StrokeStyle.Join = MiterJoin
+			case "round":
+				s.StrokeStyle.Join = RoundJoin
+			case "bevel":
+				s.StrokeStyle.Join = BevelJoin
+			}
+		case "stroke-linecap":
+			switch v {
+			case "butt":
+				s.StrokeStyle.Cap = ButtCap
+			case "round":
+				s.StrokeStyle.Cap = RoundCap
+			case "square":
+				s.StrokeStyle.Cap = SquareCap
+			}
+		case "stroke-width":
+			if f, ok := parseLength(v); ok && f >= 0 {
+				s.StrokeStyle.Width = f
+			}
+		case "stroke-miterlimit":
+			if f, err := strconv.ParseFloat(v, 32); err == nil && f >= 1 {
+				s.StrokeStyle.MiterLimit = float32(f)
+			}
+		case "fill-opacity":
+			if f, ok := parseOpacity(v); ok {
+				s.FillOpacity = f
+			}
+		case "stroke-opacity":
+			if f, ok := parseOpacity(v); ok {
+				s.StrokeOpacity = f
+			}
+		case "opacity":
+			if f, ok := parseOpacity(v); ok {
+				s.Opacity *= f
+			}
+		case "color":
+			if c, ok := parseColor(v); ok {
+				s.Color = c
+			}
+		case "visibility":
+			switch v {
+			case "visible":
+				s.Visible = true
+			case "hidden", "collapse":
+				s.Visible = false
+			}
+		}
+	}
+	return s
+}
+
+// declarations return presentation attributes of element overridden by its 'style' attribute.
+// Property names are lower case, and '!important' is dropped
+func (s *node) declarations() map[string]string {
+	res := make(map[string]string)
+	for name, v := range s.attrs {
+		if name != "style" {
+			res[name] = strings.TrimSpace(v)
+		}
+	}
+	for _, decl := range strings.Split(s.attrs["style"], ";") {
+		colon := strings.IndexByte(decl, ':')
+		if colon < 0 {
+			continue
+		}
+		v := strings.TrimSpace(decl[colon+1:])
+		v = strings.TrimSpace(strings.TrimSuffix(v, "!important"))
+		res[strings.ToLower(strings.TrimSpace(decl[:colon]))] = v
+	}
+	return res
+}
+
+// displayed report 'display' of element is not none, which hides whole subtree
+func (s *node) displayed() bool {
+	return s.declarations()["display"] != "none"
+}
+
+func parseOpacity(v string) (float32, bool) {
+	scale := 1.
+	if strings.HasSuffix(v, "%") {
+		v, scale = strings.TrimSuffix(v, "%"), .01
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(v), 32)
+	if err != nil {
+		return 0, false
+	}
+	return clamp01(float32(f * scale)), true
+}
+
+// parsePaint parse none, currentColor, color and url(#id) with optional fallback
+func parsePaint(v string) (Paint, bool) {
+	switch strings.ToLower(v) {
+	case "none":
+		return Paint{None: true}, true
+	case "currentcolor":
+		return Paint{current: true}, true
+	}
+	if strings.HasPrefix(v, "url(") {
+		end := strings.IndexByte(v, ')')
+		if end < 0 {
+			return Paint{}, false
+		}
+		url := strings.Trim(strings.TrimSpace(v[4:end]), `"'`)
+		if !strings.HasPrefix(url, "#") {
+			return Paint{}, false
+		}
+		res := Paint{URL: url[1:]}
+		// missing fallback is none
+		fallback := strings.TrimSpace(v[end+1:])
+		if fallback == "" {
+			fallback = "none"
+		}
+		p, ok := parsePaint(fallback)
+		if !ok || p.URL != "" {
+			return Paint{}, false
+		}
+		res.None, res.Color, res.current = p.None, p.Color, p.current
+		return res, true
+	}
+	c, ok := parseColor(v)
+	return Paint{Color: c}, ok
+}
+
+// parseColor parse #rgb, #rgba, #rrggbb, #rrggbbaa, rgb(), rgba() and named color.
+// https://www.w3.org/TR/css-color-3/
+func parseColor(v string) (color.NRGBA, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if strings.HasPrefix(v, "#") {
+		hex := v[1:]
+		if len(hex) == 3 || len(hex) == 4 {
+			var long []byte
+			for i := range hex {
+				long = append(long, hex[i], hex[i])
+			}
+			hex = string(long)
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 8 {
+			return color.NRGBA{}, false
+		}
+		return color.NRGBA{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, true
+	}
+	if strings.HasPrefix(v, "rgb(") || strings.HasPrefix(v, "rgba(") {
+		open, end := strings.IndexByte(v, '('), strings.LastIndexByte(v, ')')
+		if end < open {
+			return color.NRGBA{}, false
+		}
+		args := strings.FieldsFunc(v[open+1:end], func(r rune) bool {
+			return r == ',' || r == '/' || r == ' ' || r == '\t' || r == '\n'
+		})
+		if len(args) != 3 && len(args) != 4 {
+			return color.NRGBA{}, false
+		}
+		var res [4]uint8
+		res[3] = 255
+		for i, a := range args {
+			var f float64
+			var err error
+			if strings.HasSuffix(a, "%") {
+				f, err = strconv.ParseFloat(strings.TrimSuffix(a, "%"), 64)
+				f *= 2.55
+			} else if f, err = strconv.ParseFloat(a, 64); i == 3 {
+				f *= 255
+			}
+			if err != nil {
+				return color.NRGBA{}, false
+			}
+			res[i] = uint8(clamp01(float32(f/255))*255 + .5)
+		}
+		return color.NRGBA{res[0], res[1], res[2], res[3]}, true
+	}
+	if v == "transparent" {
+		return color.NRGBA{}, true
+	}
+	n, ok := namedColors[v]
+	return color.NRGBA{uint8(n >> 16), uint8(n >> 8), uint8(n), 255}, ok
+}
+
+// https://www.w3.org/TR/css-color-3/#svg-color
+var namedColors = map[string]uint32{
+	"aliceblue": 0xf0f8ff, "antiquewhite": 0xfaebd7, "aqua": 0x00ffff, "aquamarine": 0x7fffd4,
+	"azure": 0xf0ffff, "beige": 0xf5f5dc, "bisque": 0xffe4c4, "black": 0x000000,
+	"blanchedalmond": 0xffebcd, "blue": 0x0000ff, "blueviolet": 0x8a2be2, "brown": 0xa52a2a,
+	"burlywood": 0xdeb887, "cadetblue": 0x5f9ea0, "chartreuse": 0x7fff00, "chocolate": 0xd2691e,
+	"coral": 0xff7f50, "cornflowerblue": 0x6495ed, "cornsilk": 0xfff8dc, "crimson": 0xdc143c,
+	"cyan": 0x00ffff, "darkblue": 0x00008b, "darkcyan": 0x008b8b, "darkgoldenrod": 0xb8860b,
+	"darkgray": 0xa9a9a9, "darkgreen": 0x006400, "darkgrey": 0xa9a9a9, "darkkhaki": 0xbdb76b,
+	"darkmagenta": 0x8b008b, "darkolivegreen": 0x556b2f, "darkorange": 0xff8c00, "darkorchid": 0x9932cc,
+	"darkred": 0x8b0000, "darksalmon": 0xe9967a, "darkseagreen": 0x8fbc8f, "darkslateblue": 0x483d8b,
+	"darkslategray": 0x2f4f4f, "darkslategrey": 0x2f4f4f, "darkturquoise": 0x00ced1, "darkviolet": 0x9400d3,
+	"deeppink": 0xff1493, "deepskyblue": 0x00bfff, "dimgray": 0x696969, "dimgrey": 0x696969,
+	"dodgerblue": 0x1e90ff, "firebrick": 0xb22222, "floralwhite": 0xfffaf0, "forestgreen": 0x228b22,
+	"fuchsia": 0xff00ff, "gainsboro": 0xdcdcdc, "ghostwhite": 0xf8f8ff, "gold": 0xffd700,
+	"goldenrod": 0xdaa520, "gray": 0x808080, "grey": 0x808080, "green": 0x008000,
+	"greenyellow": 0xadff2f, "honeydew": 0xf0fff0, "hotpink": 0xff69b4, "indianred": 0xcd5c5c,
+	"indigo": 0x4b0082, "ivory": 0xfffff0, "khaki": 0xf0e68c, "lavender": 0xe6e6fa,
+	"lavenderblush": 0xfff0f5, "lawngreen": 0x7cfc00, "lemonchiffon": 0xfffacd, "lightblue": 0xadd8e6,
+	"lightcoral": 0xf08080, "lightcyan": 0xe0ffff, "lightgoldenrodyellow": 0xfafad2, "lightgray": 0xd3d3d3,
+	"lightgreen": 0x90ee90, "lightgrey": 0xd3d3d3, "lightpink": 0xffb6c1, "lightsalmon": 0xffa07a,
+	"lightseagreen": 0x20b2aa, "lightskyblue": 0x87cefa, "lightslategray": 0x778899, "lightslategrey": 0x778899,
+	"lightsteelblue": 0xb0c4de, "lightyellow": 0xffffe0, "lime": 0x00ff00, "limegreen": 0x32cd32,
+	"linen": 0xfaf0e6, "magenta": 0xff00ff, "maroon": 0x800000, "mediumaquamarine": 0x66cdaa,
+	"mediumblue": 0x0000cd, "mediumorchid": 0xba55d3, "mediumpurple": 0x9370db, "mediumseagreen": 0x3cb371,
+	"mediumslateblue": 0x7b68ee, "mediumspringgreen": 0x00fa9a, "mediumturquoise": 0x48d1cc, "mediumvioletred": 0xc71585,
+	"midnightblue": 0x191970, "mintcream": 0xf5fffa, "mistyrose": 0xffe4e1, "moccasin": 0xffe4b5,
+	"navajowhite": 0xffdead, "navy": 0x000080, "oldlace": 0xfdf5e6, "olive": 0x808000,
+	"olivedrab": 0x6b8e23, "orange": 0xffa500, "orangered": 0xff4500, "orchid": 0xda70d6,
+	"palegoldenrod": 0xeee8aa, "palegreen": 0x98fb98, "paleturquoise": 0xafeeee, "palevioletred": 0xdb7093,
+	"papayawhip": 0xffefd5, "peachpuff": 0xffdab9, "peru": 0xcd853f, "pink": 0xffc0cb,
+	"plum": 0xdda0dd, "powderblue": 0xb0e0e6, "purple": 0x800080, "rebeccapurple": 0x663399,
+	"red": 0xff0000, "rosybrown": 0xbc8f8f, "royalblue": 0x4169e1, "saddlebrown": 0x8b4513,
+	"salmon": 0xfa8072, "sandybrown": 0xf4a460, "seagreen": 0x2e8b57, "seashell": 0xfff5ee,
+	"sienna": 0xa0522d, "silver": 0xc0c0c0, "skyblue": 0x87ceeb, "slateblue": 0x6a5acd,
+	"slategray": 0x708090, "slategrey": 0x708090, "snow": 0xfffafa, "springgreen": 0x00ff7f,
+	"steelblue": 0x4682b4, "tan": 0xd2b48c, "teal": 0x008080, "thistle": 0xd8bfd8,
+	"tomato": 0xff6347, "turquoise": 0x40e0d0, "violet": 0xee82ee, "wheat": 0xf5deb3,
+	"white": 0xffffff, "whitesmoke": 0xf5f5f5, "yellow": 0xffff00, "yellowgreen": 0x9acd32,
+}
@@ -0,0 +1,74 @@
+package psvg
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+const styleTest = `<svg xmlns="http://www.w3.org/2000/svg" fill="red" stroke-width="2">
+	<defs>
+		<circle id="dot" r="1" fill="currentColor"/>
+	</defs>
+	<g style="stroke: #00f; stroke-linejoin: round; opacity: .5" color="lime">
+		<rect id="a" width="1" height="1" stroke-width="3" style="stroke-width: 4px; fill-rule: evenodd"/>
+		<g opacity="50%" fill="inherit" style="visibility: hidden">
+			<rect id="b" width="1" height="1" stroke="none" fill-opacity="2"/>
+		</g>
+		<use href="#dot"/>
+	</g>
+	<rect id="c" width="1" height="1" fill="url(#gradient) rgb(0, 128, 255)" stroke="wrong" stroke-linecap="square"/>
+	<g style="display:none"><rect id="hidden" width="1" height="1"/></g>
+</svg>`
+
+func TestStyle(t *testing.T) {
+	doc, err := NewDocumentFromReader(strings.NewReader(styleTest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shapes := doc.Shapes()
+	if len(shapes) != 4 {
+		t.Fatal("must have 4 shapes, but", len(shapes))
+	}
+	a, b, dot, c := shapes[0].Style, shapes[1].Style, shapes[2].Style, shapes[3].Style
+	red, blue, lime := color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}, color.NRGBA{0, 255, 0, 255}
+	if a.Fill.Color != red || a.Stroke.Color != blue || a.Stroke.None {
+		t.Error("a must be filled with red and stroked with blue, but", a.Fill, a.Stroke)
+	}
+	if a.StrokeStyle.Width != 4 || a.StrokeStyle.Join != RoundJoin || a.FillRule != EvenOdd || a.Opacity != .5 || !a.Visible {
+		t.Error("unexpected style of a", a)
+	}
+	if b.Fill.Color != red || !b.Stroke.None || b.FillOpacity != 1 || b.Opacity != .25 || b.Visible {
+		t.Error("unexpected style of b", b)
+	}
+	if dot.Fill.Color != lime || dot.StrokeStyle.Width != 2 {
+		t.Error("dot must inherit style from use, but", dot)
+	}
+	if c.Fill.URL != "gradient" || c.Fill.Color != (color.NRGBA{0, 128, 255, 255}) || !c.Stroke.None || c.StrokeStyle.Cap != SquareCap {
+		t.Error("unexpected style of c", c)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	for src, expect := range map[string]color.NRGBA{
+		"#abc":                 {0xaa, 0xbb, 0xcc, 255},
+		"#ABCD":                {0xaa, 0xbb, 0xcc, 0xdd},
+		"#102030":              {0x10, 0x20, 0x30, 255},
+		"#10203040":            {0x10, 0x20, 0x30, 0x40},
+		"rgb(10, 20, 300)":     {10, 20, 255, 255},
+		"rgb(100%, 0%, 50%)":   {255, 0, 128, 255},
+		"rgba(10, 20, 30, .5)": {10, 20, 30, 128},
+		"rgb(10 20 30 / 50%)":  {10, 20, 30, 128},
+		" RebeccaPurple ":      {0x66, 0x33, 0x99, 255},
+		"transparent":          {},
+	} {
+		if c, ok := parseColor(src); !ok || c != expect {
+			t.Error(src, "must be", expect, "but", c, ok)
+		}
+	}
+	for _, src := range []string{"", "#12", "#ggg", "rgb(1, 2)", "rgb(a, b, c)", "unknown"} {
+		if _, ok := parseColor(src); ok {
+			t.Error(src, "must be invalid")
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package psvg
+
+import (
+	"bytes"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/iamGreedy/psvg/seg"
+	"github.com/pkg/errors"
+	"math"
+	"strings"
+)
+
+// ParseTransform parse 'transform' attribute to matrix, which maps user space to parent.
+// https://www.w3.org/TR/SVG11/coords.html#TransformAttribute
+// Transforms in list are applied from right to left, as SVG does.
+// On error, whole attribute is invalid and it return identity
+func ParseTransform(src string) (mgl32.Mat3, error) {
+	res := mgl32.Ident3()
+	bts := []byte(src)
+	for {
+		bts = bytes.TrimLeft(bts, " \t\n\r\f,")
+		if len(bts) == 0 {
+			return res, nil
+		}
+		open := bytes.IndexByte(bts, '(')
+		end := bytes.IndexByte(bts, ')')
+		if open < 0 || end < open {
+			return mgl32.Ident3(), errors.Errorf("transform '%s' has no arguments", strings.TrimSpace(string(bts)))
+		}
+		name := string(bytes.TrimSpace(bts[:open]))
+		var args []float32
+		if inner := bytes.TrimSpace(bts[open+1 : end]); len(inner) > 0 {
+			var err error
+			if args, err = floats(inner); err != nil {
+				return mgl32.Ident3(), errors.Wrapf(err, "transform %s", name)
+			}
+		}
+		m, err := transformFunction(name, args)
+		if err != nil {
+			return mgl32.Ident3(), err
+		}
+		res = res.Mul3(m)
+		bts = bts[end+1:]
+	}
+}
+
+func transformFunction(name string, args []float32) (mgl32.Mat3, error) {
+	count := func(n ...int) error {
+		for _, c := range n {
+			if len(args) == c {
+				return nil
+			}
+		}
+		return errors.Errorf("transform %s can not have %d arguments", name, len(args))
+	}
+	switch name {
+	case "matrix":
+		if err := count(6); err != nil {
+			return mgl32.Ident3(), err
+		}
+		return mgl32.Mat3{args[0], args[1], 0, args[2], args[3], 0, args[4], args[5], 1}, nil
+	case "translate":
+		if err := count(1, 2); err != nil {
+			return mgl32.Ident3(), err
+		}
+		args = append(args, 0)
+		return mgl32.Translate2D(args[0], args[1]), nil
+	case "scale":
+		if err := count(1, 2); err != nil {
+			return mgl32.Ident3(), err
+		}
+		args = append(args, args[0])
+		return mgl32.Scale2D(args[0], args[1]), nil
+	case "rotate":
+		if err := count(1, 3); err != nil {
+			return mgl32.Ident3(), err
+		}
+		r := mgl32.HomogRotate2D(mgl32.DegToRad(args[0]))
+		if len(args) == 3 {
+			r = mgl32.Translate2D(args[1], args[2]).Mul3(r).Mul3(mgl32.Translate2D(-args[1], -args[2]))
+		}
+		return r, nil
+	case "skewX":
+		if err := count(1); err != nil {
+			return mgl32.Ident3(), err
+		}
+		return mgl32.Mat3{1, 0, 0, float32(math.Tan(float64(mgl32.DegToRad(args[0])))), 1, 0, 0, 0, 1}, nil
+	case "skewY":
+		if err := count(1); err != nil {
+			return mgl32.Ident3(), err
+		}
+		return mgl32.Mat3{1, float32(math.Tan(float64(mgl32.DegToRad(args[0])))), 0, 0, 1, 0, 0, 0, 1}, nil
+	}
+	return mgl32.Ident3(), errors.Errorf("unknown transform '%s'", name)
+}
+
+// Transform return path transformed by 'm', made of absolute commands.
+// Arcs stay arcs, except singular 'm' that flattens their ellipse
+func (s *Renderer) Transform(m mgl32.Mat3) (res []Elem) {
+	singular := m[0]*m[4]-m[1]*m[3] == 0
+	for _, sp := range s.subpaths() {
+		res = append(res, MoveToAbs{To: transformPoint(m, sp.start)})
+		for _, g := range sp.segs {
+			switch {
+			case g.kind != seg.ARC_ABS:
+				res = append(res, g.transform(m).elem())
+			case singular:
+				for _, c := range g.cubics() {
+					res = append(res, c.transform(m).elem())
+				}
+			default:
+				res = append(res, g.transformArc(m).elem())
+			}
+		}
+		if sp.closed {
+			res = append(res, ClosePath{})
+		}
+	}
+	return res
+}
+
+// transformArc transform arc and its ellipse by non singular 'm'
+func (g segment) transformArc(m mgl32.Mat3) segment {
+	res := g.transform(m)
+	res.radius, res.angle = transformEllipse(m, g.radius, g.angle)
+	// reflection reverses direction
+	if m[0]*m[4]-m[1]*m[3] < 0 {
+		res.sweep = !g.sweep
+	}
+	return res
+}
+
+// transformEllipse return radii and angle in degree of ellipse transformed by 'm',
+// by singular value decomposition of m * rotate(angle) * scale(radius)
+func transformEllipse(m mgl32.Mat3, radius mgl32.Vec2, angle float32) (mgl32.Vec2, float32) {
+	sin, cos := math.Sincos(float64(angle) * math.Pi / 180)
+	rx, ry := float64(radius[0]), float64(radius[1])
+	a := float64(m[0])*cos*rx + float64(m[3])*sin*rx
+	c := float64(m[1])*cos*rx + float64(m[4])*sin*rx
+	b := -float64(m[0])*sin*ry + float64(m[3])*cos*ry
+	d := -float64(m[1])*sin*ry + float64(m[4])*cos*ry
+	e, f := (a+d)/2, (a-d)/2
+	g, h := (c+b)/2, (c-b)/2
+	q, r := math.Hypot(e, h), math.Hypot(f, g)
+	phi := (math.Atan2(h, e) + math.Atan2(g, f)) / 2
+	return mgl32.Vec2{float32(q + r), float32(math.Abs(q - r))}, float32(phi * 180 / math.Pi)
+}
@@ -0,0 +1,70 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+func TestParseTransform(t *testing.T) {
+	apply := func(m mgl32.Mat3, x, y float32) mgl32.Vec2 {
+		return m.Mul3x1(mgl32.Vec3{x, y, 1}).Vec2()
+	}
+	for src, expect := range map[string]mgl32.Vec2{
+		"":                                  {1, 2},
+		"matrix(1 2 3 4 5 6)":               {12, 16},
+		"translate(10)":                     {11, 2},
+		"translate(10,-5)":                  {11, -3},
+		"scale(2)":                          {2, 4},
+		"scale(2 -1)":                       {2, -2},
+		"rotate(90)":                        {-2, 1},
+		"rotate(90 1 1)":                    {0, 1},
+		"skewX(45)":                         {3, 2},
+		"skewY(45)":                         {1, 3},
+		"translate(10,0) scale(2)":          {12, 4},
+		"scale(2),translate(10,0)":          {22, 4},
+		"\n translate(10 0)\t, rotate(180)": {9, -2},
+	} {
+		m, err := ParseTransform(src)
+		if err != nil {
+			t.Error(src, err)
+			continue
+		}
+		if p := apply(m, 1, 2); !p.ApproxEqualThreshold(expect, 1e-5) {
+			t.Error(src, "must map (1, 2) to", expect, "but", p)
+		}
+	}
+	for _, src := range []string{"translate(1,2,3)", "rotate(1 2)", "move(1)", "scale(1", "translate(x)"} {
+		if m, err := ParseTransform(src); err == nil || m != mgl32.Ident3() {
+			t.Error(src, "must be error")
+		}
+	}
+}
+
+func TestRenderer_Transform(t *testing.T) {
+	path := NewRenderer(append(Ellipse(1, 2, 3, 1), ArcAbs{To: mgl32.Vec2{8, 2}, Radius: mgl32.Vec2{2, 1}, Angle: 30, Sweep: true})...)
+	for name, m := range map[string]mgl32.Mat3{
+		"scale":   mgl32.Scale2D(2, 3),
+		"rotate":  mgl32.HomogRotate2D(1).Mul3(mgl32.Translate2D(5, -1)),
+		"skew":    {1, 0, 0, 1, 1, 0, 0, 0, 1},
+		"reflect": mgl32.Scale2D(-1, 2).Mul3(mgl32.HomogRotate2D(.5)),
+	} {
+		res := NewRenderer(path.Transform(m)...)
+		det := m[0]*m[4] - m[1]*m[3]
+		if a, b := res.Area(), path.Area()*det; !mgl32.FloatEqualThreshold(a, b, 1e-3) {
+			t.Error(name, "must have area", b, "but", a)
+		}
+		from, to := path.subpaths(), res.subpaths()
+		for i := range from {
+			for j, g := range from[i].segs {
+				for _, u := range []float32{.25, .5, .75} {
+					if p, q := transformPoint(m, g.at(u)), to[i].segs[j].at(u); !p.ApproxEqualThreshold(q, 1e-3) {
+						t.Error(name, "segment", j, "must pass", p, "but", q)
+					}
+				}
+			}
+		}
+	}
+	if res := NewRenderer(path.Transform(mgl32.Scale2D(1, 0))...); res.Area() != 0 {
+		t.Error("singular transform must make no area, but", res.Area())
+	}
+}
@@ -0,0 +1,135 @@
+package psvg
+
+import (
+	"math"
+)
+
+// Trim return visible part of path like trim paths of After Effects and Lottie.
+// 'start' and 'end' are fractions of total length in [0, 1], 'offset' shifts both of them
+// and visible part wraps around end of path.
+// Whole path is treated as one, subpaths are visited in order
+func (s *Renderer) Trim(start, end, offset float32) (res []Elem) {
+	sps := s.subpaths()
+	ranges := trimRanges(start, end, offset)
+	if len(ranges) == 1 && ranges[0] == [2]float32{0, 1} {
+		for _, sp := range sps {
+			res = append(res, sp.elems()...)
+		}
+		return res
+	}
+	var total float32
+	lengths := make([][]float32, len(sps))
+	for i, sp := range sps {
+		lengths[i] = segmentLengths(sp.drawn())
+		total += sum32(lengths[i])
+	}
+	for _, r := range ranges {
+		a, b := r[0]*total, r[1]*total
+		done := float32(0)
+		for i, sp := range sps {
+			l := sum32(lengths[i])
+			if from, to := max32(a-done, 0), min32(b-done, l); from < to {
+				res = append(res, trimSection(sp.drawn(), lengths[i], from, to)...)
+			}
+			done += l
+		}
+	}
+	return res
+}
+
+// TrimEach is Trim applied to each subpath with its own length.
+// Visible part of closed subpath, which wraps around its start, is kept connected
+func (s *Renderer) TrimEach(start, end, offset float32) (res []Elem) {
+	ranges := trimRanges(start, end, offset)
+	for _, sp := range s.subpaths() {
+		segs := sp.drawn()
+		lengths := segmentLengths(segs)
+		total := sum32(lengths)
+		if sp.closed && len(ranges) == 2 {
+			res = append(res, trimSection(segs, lengths, ranges[0][0]*total, total)...)
+			if tail := trimSection(segs, lengths, 0, ranges[1][1]*total); len(tail) > 0 {
+				res = append(res, tail[1:]...)
+			}
+			continue
+		}
+		if len(ranges) == 1 && ranges[0] == [2]float32{0, 1} {
+			res = append(res, sp.elems()...)
+			continue
+		}
+		for _, r := range ranges {
+			res = append(res, trimSection(segs, lengths, r[0]*total, r[1]*total)...)
+		}
+	}
+	return res
+}
+
+// trimRanges return visible ranges in [0, 1], which are 2 when it wraps around
+func trimRanges(start, end, offset float32) [][2]float32 {
+	start, end = clamp01(start), clamp01(end)
+	if start > end {
+		start, end = end, start
+	}
+	if end-start >= 1 {
+		return [][2]float32{{0, 1}}
+	}
+	if start == end {
+		return nil
+	}
+	shift := offset - float32(math.Floor(float64(start+offset)))
+	start, end = start+shift, end+shift
+	if end <= 1 {
+		return [][2]float32{{start, end}}
+	}
+	return [][2]float32{{start, 1}, {0, end - 1}}
+}
+
+// trimSection return part of segments between length 'from' and 'to'
+func trimSection(segs []segment, lengths []float32, from, to float32) (res []Elem) {
+	if from >= to {
+		return nil
+	}
+	done := float32(0)
+	for i, g := range segs {
+		l := lengths[i]
+		a, b := from-done, to-done
+		done += l
+		if b <= 0 || a >= l {
+			continue
+		}
+		piece := g
+		if b < l {
+			piece, _ = piece.split(g.paramAt(b))
+		}
+		if a > 0 {
+			// parameter on the left piece
+			t := g.paramAt(a)
+			if tb := g.paramAt(min32(b, l)); tb > 0 {
+				_, piece = piece.split(t / tb)
+			}
+		}
+		if len(res) == 0 {
+			res = append(res, MoveToAbs{To: piece.from})
+		}
+		res = append(res, piece.elem())
+	}
+	return res
+}
+
+func segmentLengths(segs []segment) []float32 {
+	res := make([]float32, len(segs))
+	for i, g := range segs {
+		res[i] = g.length(1)
+	}
+	return res
+}
+
+func sum32(fs []float32) (res float32) {
+	for _, f := range fs {
+		res += f
+	}
+	return res
+}
+
+func clamp01(f float32) float32 {
+	return min32(max32(f, 0), 1)
+}
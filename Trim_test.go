@@ -0,0 +1,37 @@
+package psvg
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Trim(t *testing.T) {
+	// 2 squares of length 40
+	r, _ := NewRendererFromReader(strings.NewReader("M0,0 H10 V10 H0 Z M20,0 H30 V10 H20 Z"))
+	for _, c := range []struct {
+		start, end, offset float32
+		length             float32
+		subpaths           int
+	}{
+		{0, 1, 0, 80, 2},
+		{0, .25, 0, 20, 1},
+		{.25, .75, 0, 40, 2},
+		{.75, .25, 0, 40, 2},
+		{.5, .5, 0, 0, 0},
+		{0, .25, .875, 20, 2},
+	} {
+		res := NewRenderer(r.Trim(c.start, c.end, c.offset)...)
+		if l := res.Length(); !mgl32.FloatEqualThreshold(l, c.length, 1e-4) || len(res.subpaths()) != c.subpaths {
+			t.Error(c, "must have length", c.length, "in", c.subpaths, "subpaths, but", l, res.subpaths())
+		}
+	}
+	// each square is trimmed, wrapped part is connected
+	res := r.TrimEach(0, .25, .875)
+	if sps := NewRenderer(res...).subpaths(); len(sps) != 2 || sps[0].start != (mgl32.Vec2{0, 5}) || sps[0].end() != (mgl32.Vec2{5, 0}) {
+		t.Error("wrapped part must be from (0, 5) to (5, 0), but", res)
+	}
+	if l := NewRenderer(res...).Length(); !mgl32.FloatEqualThreshold(l, 20, 1e-4) {
+		t.Error("length must be 20, but", l)
+	}
+}
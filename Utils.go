@@ -28,41 +28,54 @@ func vectors(bts []byte) (res []mgl32.Vec2, err error) {
 	}
 	return res, nil
 }
+// floats split numbers by white space, comma and sign, sign of exponent is kept.
+// On error, numbers before it are returned with the error
 func floats(bts []byte) (res []float32, err error) {
 	bts = bytes.TrimSpace(bts)
 	var from = 0
+	// current number has '.' or exponent, so next '.' starts new number
+	var dot = false
 	for to, b := range bts {
 		var temp float64
 		switch b {
-		case ' ':
-			fallthrough
-		case ',':
-			if from == to{
+		case ' ', ',', '\t', '\n', '\r', '\f':
+			if from == to {
+				from = to + 1
 				continue
 			}
 			temp, err = strconv.ParseFloat(string(bts[from:to]), 32)
 			if err != nil {
-				return nil, err
+				return res, err
 			}
 			res = append(res, float32(temp))
-			from = to + 1
-		case '+':
+			from, dot = to+1, false
+		case '.':
+			if !dot {
+				dot = true
+				continue
+			}
 			fallthrough
-		case '-':
-			if from == to{
+		case '+', '-':
+			if b != '.' && to > from && (bts[to-1] == 'e' || bts[to-1] == 'E') {
+				continue
+			}
+			if from == to {
+				dot = b == '.'
 				continue
 			}
 			temp, err = strconv.ParseFloat(string(bts[from:to]), 32)
 			if err != nil {
-				return nil, err
+				return res, err
 			}
 			res = append(res, float32(temp))
-			from = to
+			from, dot = to, b == '.'
+		case 'e', 'E':
+			dot = true
 		}
 	}
 	temp, err := strconv.ParseFloat(string(bts[from:]), 32)
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 	res = append(res, float32(temp))
 	return